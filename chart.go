@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+	"gonum.org/v1/plot/vg/vgpdf"
+	"gonum.org/v1/plot/vg/vgsvg"
+)
+
+const (
+	minChartDPI = 72
+	maxChartDPI = 600
+	// wymiary w calach
+	minChartInches = 3
+	maxChartInches = 30
+	// maxChartPixels ogranicza bufor RGBA rysowanego PNG (4 bajty na piksel, ok. 64 MB).
+	// Discord limituje rozmiar pliku, nie liczbę pikseli, ale tak duży obraz i tak by się nie zmieścił.
+	maxChartPixels = 16_000_000
+)
+
+// chartOptions opisuje format i wymiary generowanego wykresu.
+type chartOptions struct {
+	Format string // png, svg albo pdf
+	DPI    int    // tylko dla PNG
+	Theme  string // nazwa z chartThemes; pusta oznacza motyw serwera
+	// Currency to waluta bazowa (np. "PLN"); pusta oznacza chart_currency z konfiguracji.
+	Currency string
+	// Momentum dodaje panel 12-miesięcznego momentum pod wykresem spadków.
+	Momentum bool
+	Width    vg.Length
+	Height   vg.Length
+}
+
+var defaultChartOptions = chartOptions{Format: "png", DPI: 96, Width: 12 * vg.Inch, Height: 8 * vg.Inch}
+
+// portraitChartSize to pionowy układ czytelny na telefonie.
+var portraitChartSize = [2]vg.Length{6 * vg.Inch, 10 * vg.Inch}
+
+var chartContentTypes = map[string]string{
+	"png": "image/png",
+	"svg": "image/svg+xml",
+	"pdf": "application/pdf",
+}
+
+func (o chartOptions) contentType() string {
+	return chartContentTypes[o.Format]
+}
+
+// fileName dokleja rozszerzenie formatu do nazwy bazowej załącznika.
+func (o chartOptions) fileName(base string) string {
+	return base + "." + o.Format
+}
+
+// chartOptionsForPath dobiera format po rozszerzeniu pliku (tryb CLI).
+func chartOptionsForPath(path string) chartOptions {
+	opts := defaultChartOptions
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if _, ok := chartContentTypes[ext]; ok {
+		opts.Format = ext
+	}
+	return opts
+}
+
+// parseChartOptions czyta argumenty komendy wykresu:
+// [png|svg|pdf] [light|dark] [momentum] [pionowy] [--dpi N] [--rozmiar SZERxWYS] [--waluta KOD]
+// (wymiary w calach).
+func parseChartOptions(args string) (chartOptions, error) {
+	opts, rest, err := parseChartArgs(strings.Fields(args))
+	if err != nil {
+		return opts, err
+	}
+	if len(rest) > 0 {
+		return opts, trError("chart.err.unknown_option", strings.ToLower(rest[0]))
+	}
+	return opts, nil
+}
+
+// parseChartArgs wybiera z argumentów opcje wykresu, a pozostałe słowa zwraca
+// w oryginalnej kolejności (np. tickery dla !wykres).
+func parseChartArgs(args []string) (chartOptions, []string, error) {
+	opts := defaultChartOptions
+	var rest []string
+	fields := make([]string, len(args))
+	for i, a := range args {
+		fields[i] = strings.ToLower(a)
+	}
+	for i := 0; i < len(fields); i++ {
+		switch f := fields[i]; {
+		case chartContentTypes[f] != "":
+			opts.Format = f
+		case isChartTheme(f):
+			opts.Theme, _ = parseChartTheme(f)
+		case f == "momentum":
+			opts.Momentum = true
+		case f == "pionowy":
+			opts.Width, opts.Height = portraitChartSize[0], portraitChartSize[1]
+		case f == "--dpi":
+			if i+1 >= len(fields) {
+				return opts, nil, trError("chart.err.missing_value", f)
+			}
+			i++
+			dpi, err := strconv.Atoi(fields[i])
+			if err != nil || dpi < minChartDPI || dpi > maxChartDPI {
+				return opts, nil, trError("chart.err.dpi", minChartDPI, maxChartDPI)
+			}
+			opts.DPI = dpi
+		case f == "--rozmiar":
+			if i+1 >= len(fields) {
+				return opts, nil, trError("chart.err.missing_value", f)
+			}
+			i++
+			w, h, ok := parseChartSize(fields[i])
+			if !ok {
+				return opts, nil, trError("chart.err.size", minChartInches, maxChartInches)
+			}
+			opts.Width, opts.Height = w, h
+		case f == "--waluta":
+			if i+1 >= len(fields) {
+				return opts, nil, trError("chart.err.missing_value", f)
+			}
+			i++
+			code := strings.ToUpper(fields[i])
+			if !currencyCodePattern.MatchString(code) {
+				return opts, nil, trError("chart.err.currency", fields[i])
+			}
+			opts.Currency = code
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+
+	if opts.Format == "png" {
+		px := float64(opts.Width/vg.Inch) * float64(opts.Height/vg.Inch) * float64(opts.DPI*opts.DPI)
+		if px > maxChartPixels {
+			return opts, nil, trError("chart.err.too_large")
+		}
+	}
+	return opts, rest, nil
+}
+
+// parseChartSize czyta wymiary w calach, np. "8x10" albo "7.5x4".
+func parseChartSize(s string) (vg.Length, vg.Length, bool) {
+	ws, hs, ok := strings.Cut(s, "x")
+	if !ok {
+		return 0, 0, false
+	}
+	w, errW := strconv.ParseFloat(ws, 64)
+	h, errH := strconv.ParseFloat(hs, 64)
+	if errW != nil || errH != nil {
+		return 0, 0, false
+	}
+	for _, v := range []float64{w, h} {
+		if v < minChartInches || v > maxChartInches {
+			return 0, 0, false
+		}
+	}
+	return vg.Length(w) * vg.Inch, vg.Length(h) * vg.Inch, true
+}
+
+// chartPanel to jeden panel wykresu; Weight to liczba wierszy siatki, którą zajmuje.
+type chartPanel struct {
+	Plot   *plot.Plot
+	Weight int
+}
+
+func newChartCanvas(opts chartOptions) (vg.CanvasWriterTo, error) {
+	switch opts.Format {
+	case "png":
+		return vgimg.PngCanvas{Canvas: vgimg.NewWith(vgimg.UseWH(opts.Width, opts.Height), vgimg.UseDPI(opts.DPI))}, nil
+	case "svg":
+		return vgsvg.New(opts.Width, opts.Height), nil
+	case "pdf":
+		return vgpdf.New(opts.Width, opts.Height), nil
+	}
+	return nil, fmt.Errorf("nieobsługiwany format wykresu: %s", opts.Format)
+}
+
+// writePanels rysuje panele jeden pod drugim na jednym obrazie i zapisuje go w formacie z opts.
+// plot.Align wyrównuje obszary danych paneli w poziomie i daje każdemu wierszowi siatki
+// tę samą wysokość obszaru danych; panel o wadze n zajmuje n kolejnych wierszy.
+func writePanels(w io.Writer, panels []chartPanel, opts chartOptions) error {
+	c, err := newChartCanvas(opts)
+	if err != nil {
+		return err
+	}
+
+	var rows [][]*plot.Plot
+	first := make([]int, len(panels))
+	for i, panel := range panels {
+		first[i] = len(rows)
+		rows = append(rows, []*plot.Plot{panel.Plot})
+		for range panel.Weight - 1 {
+			rows = append(rows, []*plot.Plot{nil})
+		}
+	}
+	tiles := plot.Align(rows, draw.Tiles{Rows: len(rows), Cols: 1}, draw.New(c))
+	for i, panel := range panels {
+		pc := tiles[first[i]][0]
+		pc.Min.Y = tiles[first[i]+max(panel.Weight, 1)-1][0].Min.Y
+		panel.Plot.Draw(pc)
+	}
+
+	_, err = c.WriteTo(w)
+	return err
+}
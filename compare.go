@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/vg"
+)
+
+const (
+	// maxCompareTickers ogranicza liczbę serii, żeby legenda i etykiety zmieściły się na obrazie.
+	maxCompareTickers = 8
+	// compareSmallLegend to liczba serii, od której legenda dostaje mniejszą czcionkę.
+	compareSmallLegend = 5
+	maxCompareMonths   = 20 * 12
+)
+
+// comparisonSpec opisuje wykres porównawczy z !wykres.
+type comparisonSpec struct {
+	Tickers  []string
+	Months   int    // zakres wstecz od dziś
+	Interval string // 1d, 1wk albo 1mo
+	Indexed  bool   // true: indeks (start = 100), false: stopa zwrotu w %
+}
+
+type comparisonSummary struct {
+	Generated time.Time
+	Spec      comparisonSpec
+	// Returns to zawsze stopy zwrotu w procentach, niezależnie od normalizacji wykresu.
+	Returns  []tickerReturn
+	Missing  []string
+	Currency string
+}
+
+var (
+	compareRangePattern  = regexp.MustCompile(`^(\d+)([my])$`)
+	compareTickerPattern = regexp.MustCompile(`^[A-Z0-9^][A-Z0-9.=^-]{0,14}$`)
+)
+
+// parseComparison czyta argumenty !wykres: tickery, zakres (np. 6m, 2y), interwał
+// (1d, 1wk, 1mo), normalizację (procent, indeks) i opcje wykresu jak w !gem.
+func parseComparison(args string) (comparisonSpec, chartOptions, error) {
+	spec := comparisonSpec{Months: 12, Interval: "1d"}
+	opts, rest, err := parseChartArgs(strings.Fields(args))
+	if err != nil {
+		return spec, opts, err
+	}
+
+	for _, arg := range rest {
+		lower := strings.ToLower(arg)
+		switch {
+		case slices.Contains(marketIntervals, lower):
+			spec.Interval = lower
+		case lower == "procent":
+			spec.Indexed = false
+		case lower == "indeks":
+			spec.Indexed = true
+		case compareRangePattern.MatchString(lower):
+			m := compareRangePattern.FindStringSubmatch(lower)
+			n, _ := strconv.Atoi(m[1])
+			if m[2] == "y" {
+				n *= 12
+			}
+			if n < 1 || n > maxCompareMonths {
+				return spec, opts, trError("compare.err.range", maxCompareMonths/12)
+			}
+			spec.Months = n
+		default:
+			ticker := strings.ToUpper(arg)
+			if !compareTickerPattern.MatchString(ticker) {
+				return spec, opts, trError("compare.err.ticker", arg)
+			}
+			if !slices.Contains(spec.Tickers, ticker) {
+				spec.Tickers = append(spec.Tickers, ticker)
+			}
+		}
+	}
+
+	if len(spec.Tickers) == 0 {
+		return spec, opts, trError("compare.err.no_tickers")
+	}
+	if len(spec.Tickers) > maxCompareTickers {
+		return spec, opts, trError("compare.err.too_many", maxCompareTickers)
+	}
+	return spec, opts, nil
+}
+
+// rangeText opisuje zakres wykresu słownie, np. "2 lata" albo "6 miesięcy".
+func (c comparisonSpec) rangeText(lang string) string {
+	if c.Months%12 == 0 {
+		return trn(lang, "compare.range.years", c.Months/12, c.Months/12)
+	}
+	return trn(lang, "compare.range.months", c.Months, c.Months)
+}
+
+// renderComparisonChart rysuje notowania dowolnych tickerów znormalizowane do początku zakresu.
+// Tickery bez danych są pomijane i trafiają do Missing; błąd jest tylko, gdy nie ma żadnych danych.
+func renderComparisonChart(ctx context.Context, w io.Writer, lang string, spec comparisonSpec, opts chartOptions) (comparisonSummary, error) {
+	loc, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		return comparisonSummary{}, err
+	}
+
+	end := time.Now().In(loc)
+	start := end.AddDate(0, -spec.Months, 0)
+	table, missing, err := fetchPriceTable(ctx, spec.Tickers, start, end, spec.Interval, true, loc)
+	if err != nil {
+		return comparisonSummary{}, err
+	}
+	currency := chartCurrency(opts)
+	table, unconverted, err := convertPriceTable(ctx, table, currency, start, end, true, loc)
+	if err != nil {
+		return comparisonSummary{}, err
+	}
+	missing = append(missing, unconverted...)
+
+	startIdx := table.firstComplete(start)
+	if len(table.Times)-startIdx < 2 {
+		return comparisonSummary{}, trError("chart.incomplete_data")
+	}
+	tickers, times := table.Tickers, table.Times[startIdx:]
+
+	ref, labelFormat, legendFormat := 0.0, returnLabelFormat, "%s: %+0.2f%%"
+	if spec.Indexed {
+		ref, labelFormat, legendFormat = 100, indexLabelFormat, "%s: %.1f"
+	}
+
+	summary := comparisonSummary{Generated: end, Spec: spec, Missing: missing, Currency: currency}
+	normalized := make(map[string][]float64, len(tickers))
+	for _, ticker := range tickers {
+		values := table.Values[ticker][startIdx:]
+		base := values[0]
+		if base == 0 {
+			return comparisonSummary{}, trError("gem.err.zero_base", ticker)
+		}
+		series := make([]float64, len(values))
+		for i, v := range values {
+			if spec.Indexed {
+				series[i] = v / base * 100
+			} else {
+				series[i] = (v/base - 1) * 100
+			}
+			if !isFinite(series[i]) {
+				return comparisonSummary{}, trError("gem.err.bad_return", ticker)
+			}
+		}
+		normalized[ticker] = series
+		summary.Returns = append(summary.Returns, tickerReturn{Ticker: ticker, Return: (values[len(values)-1]/base - 1) * 100})
+	}
+
+	theme, ok := chartThemes[opts.Theme]
+	if !ok {
+		theme = chartThemes[defaultChartTheme]
+	}
+	colors := theme.seriesColors(tickers)
+
+	xMin := float64(times[0].Unix())
+	xMax := float64(times[len(times)-1].Unix())
+	xMax += (xMax - xMin) / 25
+	yMin, yMax := seriesRange(normalized, ref, 10)
+
+	p := newChartPanel(theme, loc, xMin, xMax, yMin, yMax)
+	if spec.Indexed {
+		p.Y.Tick.Marker = plot.DefaultTicks{}
+	}
+	p.Title.Text = fmt.Sprintf("%s (%s)", tr(lang, "compare.title", spec.rangeText(lang)), currency)
+	p.X.Label.Text = tr(lang, "compare.interval."+spec.Interval)
+	for _, ticker := range tickers {
+		series := normalized[ticker]
+		line, err := seriesLine(theme, colors[ticker], times, series)
+		if err != nil {
+			return comparisonSummary{}, err
+		}
+		p.Add(line)
+		p.Legend.Add(fmt.Sprintf(legendFormat, ticker, series[len(series)-1]), line)
+	}
+	p.Legend.Top = true
+	p.Legend.Left = true
+	p.Legend.XOffs = vg.Points(6)
+	p.Legend.YOffs = vg.Points(-6)
+	if len(tickers) > compareSmallLegend {
+		p.Legend.TextStyle.Font = font.From(theme.Font, theme.TickSize)
+	}
+	axis := rightAxis(theme, p, panelLabels(colors, tickers, normalized, labelFormat))
+	p.Add(axis)
+
+	if err := writePanels(w, []chartPanel{{Plot: p, Weight: 1}}, opts); err != nil {
+		return comparisonSummary{}, err
+	}
+	return summary, nil
+}
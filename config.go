@@ -2,7 +2,11 @@ package main
 
 import (
 	"encoding/json"
+	"log/slog"
 	"os"
+	"slices"
+	"sync"
+	"time"
 )
 
 type Config struct {
@@ -10,11 +14,59 @@ type Config struct {
 	ChannelID      string   `json:"channel_id"`
 	GemChannelID   string   `json:"gem_channel_id"`
 	GemSubscribers []string `json:"gem_subscribers"`
+	// GemPartialResults rysuje wykres bez tickerów, których nie udało się pobrać,
+	// zamiast przerywać całe generowanie.
+	GemPartialResults bool `json:"gem_partial_results,omitempty"`
+	// ChartCurrency to waluta, na którą wykresy przeliczają ceny (kod ISO, domyślnie PLN).
+	ChartCurrency string `json:"chart_currency,omitempty"`
+
+	PlainTextChannels []string `json:"plain_text_channels,omitempty"`
+
+	Jobs    map[string]JobConfig `json:"jobs,omitempty"`
+	JobRuns map[string]time.Time `json:"job_runs,omitempty"`
+
+	AlertChannelID string `json:"alert_channel_id,omitempty"`
+	AlertUserID    string `json:"alert_user_id,omitempty"`
+	// AlertGuildID to serwer, z którego włączono alerty w DM; wyznacza ich język dla zadań bez własnego kanału.
+	AlertGuildID string `json:"alert_guild_id,omitempty"`
+
+	WeatherLocations     []WeatherLocation     `json:"weather_locations"`
+	WeatherSubscriptions []WeatherSubscription `json:"weather_subscriptions,omitempty"`
+	// WeatherThresholds to progi ostrzeżeń według nazwy lokalizacji (małymi literami).
+	WeatherThresholds map[string]WeatherThresholds `json:"weather_thresholds,omitempty"`
+	SentWeatherAlerts map[string]time.Time         `json:"sent_weather_alerts,omitempty"`
+	UserWeatherUnits  map[string]WeatherUnits      `json:"user_weather_units,omitempty"`
+	GuildWeatherUnits map[string]WeatherUnits      `json:"guild_weather_units,omitempty"`
+
+	// GuildLanguages to język bota ("pl", "en") według ID serwera.
+	GuildLanguages map[string]string `json:"guild_languages,omitempty"`
+	// GuildChartThemes to domyślny motyw wykresów ("light", "dark") według ID serwera.
+	GuildChartThemes map[string]string `json:"guild_chart_themes,omitempty"`
+
+	Reminders      []Reminder `json:"reminders,omitempty"`
+	NextReminderID int        `json:"next_reminder_id,omitempty"`
+
+	PriceAlerts      []PriceAlert `json:"price_alerts,omitempty"`
+	NextPriceAlertID int          `json:"next_price_alert_id,omitempty"`
+}
+
+type JobConfig struct {
+	Spec     string `json:"spec"`
+	Timezone string `json:"timezone,omitempty"`
+	Enabled  bool   `json:"enabled"`
+	// CatchUpWindow określa, jak dawno pominięte uruchomienie nadrobić po starcie (np. "36h").
+	CatchUpWindow string `json:"catch_up_window,omitempty"`
+	Retries       int    `json:"retries,omitempty"`
+	// RetryBackoff to odstęp przed pierwszym ponowieniem, podwajany przy kolejnych.
+	RetryBackoff string `json:"retry_backoff,omitempty"`
 }
 
 var (
 	config     Config
 	configFile = "config.json"
+	configMu   sync.Mutex
+	// configWriteMu szereguje zapisy pliku, żeby dwa zapisy nie przeplotły się na dysku.
+	configWriteMu sync.Mutex
 )
 
 func loadConfig() {
@@ -30,13 +82,112 @@ func loadConfig() {
 			GemChannelID:   "",
 			GemSubscribers: nil,
 		}
+		applyJobDefaults()
+		applyWeatherDefaults()
 		saveConfig()
 		return
 	}
 	json.Unmarshal(data, &config)
+	applyJobDefaults()
+	applyWeatherDefaults()
+}
+
+func applyJobDefaults() {
+	if config.Jobs == nil {
+		config.Jobs = make(map[string]JobConfig, len(scheduledJobs))
+	}
+	for _, job := range scheduledJobs {
+		if _, ok := config.Jobs[job.Name]; !ok {
+			config.Jobs[job.Name] = job.Default
+		}
+	}
+}
+
+// updateConfig wprowadza zmianę pod configMu i zapisuje plik już po zwolnieniu blokady.
+// Komendy zmieniają konfigurację tylko tędy, żeby nie ścigać się z zadaniami crona,
+// które w tym czasie serializują albo aktualizują te same mapy.
+func updateConfig(change func(c *Config)) {
+	configMu.Lock()
+	change(&config)
+	configMu.Unlock()
+	saveConfig()
+}
+
+// readConfig odczytuje wartość z konfiguracji pod configMu, np. identyfikator kanału w zadaniu crona.
+func readConfig[T any](get func(c *Config) T) T {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return get(&config)
+}
+
+// quotes zwraca kopię listy cytatów do odczytu poza blokadą.
+func quotes() []string {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return slices.Clone(config.Quotes)
+}
+
+// weatherLocations zwraca kopię stałej listy miejscowości do odczytu poza blokadą.
+func weatherLocations() []WeatherLocation {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return slices.Clone(config.WeatherLocations)
 }
 
 func saveConfig() {
-	data, _ := json.MarshalIndent(config, "", "  ")
-	os.WriteFile(configFile, data, 0o644)
+	if err := writeConfig(); err != nil {
+		slog.Error("błąd zapisu konfiguracji", "error", err)
+	}
+}
+
+// writeConfig zapisuje konfigurację i czeka na zrzut na dysk. Nie używa pliku
+// tymczasowego z rename, bo config.json jest montowany w kontenerze jako pojedynczy plik.
+func writeConfig() error {
+	configWriteMu.Lock()
+	defer configWriteMu.Unlock()
+
+	configMu.Lock()
+	data, err := json.MarshalIndent(config, "", "  ")
+	configMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(configFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// applyWeatherDefaults uzupełnia lokalizacje tylko, gdy pola nie ma w pliku;
+// pusta lista zapisana przez użytkownika zostaje pusta.
+func applyWeatherDefaults() {
+	if config.WeatherLocations == nil {
+		config.WeatherLocations = append([]WeatherLocation(nil), defaultWeatherLocations...)
+	}
+}
+
+func lastJobRun(name string) time.Time {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return config.JobRuns[name]
+}
+
+func recordJobRun(name string, at time.Time) {
+	configMu.Lock()
+	if config.JobRuns == nil {
+		config.JobRuns = make(map[string]time.Time)
+	}
+	config.JobRuns[name] = at
+	configMu.Unlock()
+	saveConfig()
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// useTempConfig podmienia plik i stan konfiguracji na czas testu.
+func useTempConfig(t *testing.T) {
+	t.Helper()
+	prevFile, prevConfig := configFile, config
+	configFile = filepath.Join(t.TempDir(), "config.json")
+	config = Config{}
+	t.Cleanup(func() {
+		configFile, config = prevFile, prevConfig
+	})
+}
+
+// Uruchamiać z -race: komendy i zadania crona zmieniają i czytają te same mapy równolegle.
+func TestUpdateConfigConcurrent(t *testing.T) {
+	useTempConfig(t)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			guildID := fmt.Sprintf("g%d", i%4)
+			updateConfig(func(c *Config) {
+				if c.GuildLanguages == nil {
+					c.GuildLanguages = make(map[string]string)
+				}
+				c.GuildLanguages[guildID] = "en"
+				c.Quotes = append(c.Quotes, guildID)
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			langFor(fmt.Sprintf("g%d", i%4))
+			quotes()
+			recordJobRun("cytat", lastJobRun("cytat"))
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved Config
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Quotes) != 20 {
+		t.Errorf("zapisano %d cytatów, want 20", len(saved.Quotes))
+	}
+	if got := langFor("g1"); got != "en" {
+		t.Errorf("langFor(g1) = %q, want en", got)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultChartCurrency = "PLN"
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// minorCurrencyUnits to kody walut notowanych w setnych częściach (np. pensy na LSE)
+// i waluty główne, do których je przeliczamy.
+var minorCurrencyUnits = map[string]string{
+	"GBp": "GBP",
+	"GBX": "GBP",
+	"ZAc": "ZAR",
+	"ILA": "ILS",
+}
+
+// chartCurrency zwraca walutę bazową wykresu: z opcji komendy, z konfiguracji albo PLN.
+func chartCurrency(opts chartOptions) string {
+	if opts.Currency != "" {
+		return opts.Currency
+	}
+	if code := strings.ToUpper(readConfig(func(c *Config) string { return c.ChartCurrency })); currencyCodePattern.MatchString(code) {
+		return code
+	}
+	return defaultChartCurrency
+}
+
+// majorCurrency zamienia kod waluty w jednostkach podrzędnych na główną i zwraca mnożnik ceny.
+func majorCurrency(code string) (string, float64) {
+	if major, ok := minorCurrencyUnits[code]; ok {
+		return major, 0.01
+	}
+	return strings.ToUpper(code), 1
+}
+
+// fxTicker to symbol kursu walutowego w Yahoo, np. USDPLN=X.
+func fxTicker(from, to string) string {
+	return from + to + "=X"
+}
+
+// convertPriceTable przelicza ceny tickerów na walutę base po dziennym kursie z Yahoo,
+// pobieranym tą samą warstwą danych co notowania. Ticker bez waluty w metadanych albo bez
+// kursu nie da się porównać z resztą: przy partial wypada z tabeli i trafia do zwracanej
+// listy pominiętych, bez partial cały wykres kończy się błędem.
+func convertPriceTable(ctx context.Context, table priceTable, base string, start, end time.Time, partial bool, loc *time.Location) (priceTable, []string, error) {
+	type conversion struct {
+		pair   string // pusty, gdy ticker jest już w walucie bazowej
+		factor float64
+	}
+	var unconverted []string
+	conversions := make(map[string]conversion, len(table.Tickers))
+	var pairs []string
+	for _, ticker := range table.Tickers {
+		code := table.Currencies[ticker]
+		if code == "" {
+			if !partial {
+				return priceTable{}, nil, trError("chart.err.no_currency", ticker, base)
+			}
+			slog.Warn("brak waluty w metadanych, pomijam ticker", "ticker", ticker, "currency", base)
+			unconverted = append(unconverted, ticker)
+			continue
+		}
+		major, factor := majorCurrency(code)
+		conv := conversion{factor: factor}
+		if major != base {
+			conv.pair = fxTicker(major, base)
+			if !slices.Contains(pairs, conv.pair) {
+				pairs = append(pairs, conv.pair)
+			}
+		}
+		conversions[ticker] = conv
+	}
+
+	var fx priceTable
+	if len(pairs) > 0 {
+		var err error
+		fx, _, err = fetchPriceTable(ctx, pairs, start, end, "1d", partial, loc)
+		if err != nil {
+			if !partial {
+				return priceTable{}, nil, err
+			}
+			// żadnego kursu; tickery w walucie bazowej nadal da się narysować
+			slog.Warn("brak kursów walut", "pairs", pairs, "error", err)
+		}
+	}
+
+	converted := priceTable{
+		Times:      table.Times,
+		Values:     make(map[string][]float64, len(table.Tickers)),
+		Currencies: make(map[string]string, len(table.Tickers)),
+	}
+	for _, ticker := range table.Tickers {
+		conv, ok := conversions[ticker]
+		if !ok {
+			continue
+		}
+		if conv.pair != "" && !slices.Contains(fx.Tickers, conv.pair) {
+			slog.Warn("brak kursu walut, pomijam ticker", "ticker", ticker, "pair", conv.pair)
+			unconverted = append(unconverted, ticker)
+			continue
+		}
+		var rates []float64
+		if conv.pair != "" {
+			rates = fx.ratesAt(conv.pair, table.Times)
+		}
+		values := table.Values[ticker]
+		out := make([]float64, len(values))
+		for i, v := range values {
+			out[i] = v * conv.factor
+			if rates != nil {
+				out[i] *= rates[i]
+			}
+		}
+		converted.Tickers = append(converted.Tickers, ticker)
+		converted.Values[ticker] = out
+		converted.Currencies[ticker] = base
+	}
+	if len(converted.Tickers) == 0 {
+		return priceTable{}, unconverted, trError("chart.err.no_currency", strings.Join(unconverted, ", "), base)
+	}
+	return converted, unconverted, nil
+}
+
+// ratesAt zwraca kurs z tabeli dla każdej z dat: ostatni znany nie później niż data,
+// a przed pierwszym notowaniem pierwszy dostępny kurs.
+func (t priceTable) ratesAt(ticker string, times []time.Time) []float64 {
+	series := t.Values[ticker]
+	first := math.NaN()
+	for _, v := range series {
+		if isFinite(v) {
+			first = v
+			break
+		}
+	}
+	rates := make([]float64, len(times))
+	for i, at := range times {
+		j := sort.Search(len(t.Times), func(k int) bool { return t.Times[k].After(at) }) - 1
+		if j < 0 || !isFinite(series[j]) {
+			rates[i] = first
+			continue
+		}
+		rates[i] = series[j]
+	}
+	return rates
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSeries to notowania jednego tickera w atrapie Yahoo.
+type fakeSeries struct {
+	Currency string
+	Times    []time.Time
+	Closes   []float64
+}
+
+// fakeYahoo odpowiada na zapytania o wykres jak Yahoo; nieznane tickery dostają 404.
+type fakeYahoo struct {
+	mu        sync.Mutex
+	series    map[string]fakeSeries
+	requested []string
+}
+
+func (f *fakeYahoo) RoundTrip(req *http.Request) (*http.Response, error) {
+	ticker, _ := url.PathUnescape(req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+	f.mu.Lock()
+	f.requested = append(f.requested, ticker)
+	s, ok := f.series[ticker]
+	f.mu.Unlock()
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+	}
+
+	var payload yahooChartResponse
+	payload.Chart.Result = make([]struct {
+		Meta struct {
+			Currency string `json:"currency"`
+		} `json:"meta"`
+		Timestamp  []int64 `json:"timestamp"`
+		Indicators struct {
+			Quote []struct {
+				Close []*float64 `json:"close"`
+			} `json:"quote"`
+		} `json:"indicators"`
+	}, 1)
+	result := &payload.Chart.Result[0]
+	result.Meta.Currency = s.Currency
+	result.Indicators.Quote = make([]struct {
+		Close []*float64 `json:"close"`
+	}, 1)
+	for i, at := range s.Times {
+		result.Timestamp = append(result.Timestamp, at.Unix())
+		result.Indicators.Quote[0].Close = append(result.Indicators.Quote[0].Close, &s.Closes[i])
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(string(body))), Request: req}, nil
+}
+
+func useFakeYahoo(t *testing.T, series map[string]fakeSeries) *fakeYahoo {
+	t.Helper()
+	fake := &fakeYahoo{series: series}
+	prev := yahooClient
+	yahooClient = &http.Client{Transport: fake}
+	t.Cleanup(func() { yahooClient = prev })
+	return fake
+}
+
+// sessionTimes zwraca kolejne dni o 16:00 UTC.
+func sessionTimes(first time.Time, n int) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = first.AddDate(0, 0, i)
+	}
+	return times
+}
+
+func TestConvertPriceTable(t *testing.T) {
+	days := sessionTimes(time.Date(2026, 10, 12, 16, 0, 0, 0, time.UTC), 3)
+	useFakeYahoo(t, map[string]fakeSeries{
+		"USDPLN=X": {Currency: "PLN", Times: days, Closes: []float64{4, 4, 5}},
+		"GBPPLN=X": {Currency: "PLN", Times: days, Closes: []float64{5, 5, 5}},
+	})
+	loc := time.UTC
+	start, end := days[0], days[2]
+	table := priceTable{
+		Times:   []time.Time{days[0].Truncate(24 * time.Hour), days[1].Truncate(24 * time.Hour), days[2].Truncate(24 * time.Hour)},
+		Tickers: []string{"SPY", "CSPX.L", "PKO.WA", "NOCUR", "CHF"},
+		Values: map[string][]float64{
+			"SPY":    {10, 10, 10},
+			"CSPX.L": {200, 200, 200},
+			"PKO.WA": {50, 51, 52},
+			"NOCUR":  {1, 1, 1},
+			"CHF":    {1, 1, 1},
+		},
+		Currencies: map[string]string{"SPY": "USD", "CSPX.L": "GBp", "PKO.WA": "PLN", "CHF": "CHF"},
+	}
+
+	converted, unconverted, err := convertPriceTable(context.Background(), table, "PLN", start, end, true, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"NOCUR", "CHF"}; !slices.Equal(unconverted, want) {
+		t.Errorf("unconverted = %v, want %v", unconverted, want)
+	}
+	if want := []string{"SPY", "CSPX.L", "PKO.WA"}; !slices.Equal(converted.Tickers, want) {
+		t.Errorf("Tickers = %v, want %v", converted.Tickers, want)
+	}
+	want := map[string][]float64{
+		"SPY":    {40, 40, 50},
+		"CSPX.L": {10, 10, 10},
+		"PKO.WA": {50, 51, 52},
+	}
+	for ticker, values := range want {
+		if got := converted.Values[ticker]; !slices.Equal(got, values) {
+			t.Errorf("%s = %v, want %v", ticker, got, values)
+		}
+		if converted.Currencies[ticker] != "PLN" {
+			t.Errorf("%s: waluta %q, want PLN", ticker, converted.Currencies[ticker])
+		}
+	}
+
+	// bez partial brak waluty albo kursu kończy się błędem
+	for _, ticker := range []string{"NOCUR", "CHF"} {
+		strict := table
+		strict.Tickers = []string{"SPY", ticker}
+		_, _, err := convertPriceTable(context.Background(), strict, "PLN", start, end, false, loc)
+		if err == nil {
+			t.Errorf("%s bez partial: brak błędu", ticker)
+		}
+	}
+	strict := table
+	strict.Tickers = []string{"NOCUR"}
+	_, _, err = convertPriceTable(context.Background(), strict, "PLN", start, end, false, loc)
+	var le *localizedError
+	if !errors.As(err, &le) || le.Key != "chart.err.no_currency" {
+		t.Errorf("err = %v, want chart.err.no_currency", err)
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	embedColorQuote   = 0xF1C40F
+	embedColorWeather = 0x3498DB
+	embedColorGem     = 0x2ECC71
+)
+
+// richMessage to wiadomość z embedem i zapasową wersją tekstową,
+// używaną gdy embedy są wyłączone na kanale albo wysyłka embeda się nie uda.
+type richMessage struct {
+	Content  string
+	Embed    *discordgo.MessageEmbed
+	Fallback string
+	Files    []*discordgo.File
+}
+
+func embedsEnabled(channelID string) bool {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return !slices.Contains(config.PlainTextChannels, channelID)
+}
+
+func setEmbedsEnabled(c *Config, channelID string, enabled bool) {
+	kept := c.PlainTextChannels[:0]
+	for _, id := range c.PlainTextChannels {
+		if id != channelID {
+			kept = append(kept, id)
+		}
+	}
+	if !enabled {
+		kept = append(kept, channelID)
+	}
+	c.PlainTextChannels = kept
+}
+
+func sendRich(s *discordgo.Session, channelID string, msg richMessage) error {
+	if msg.Embed != nil && embedsEnabled(channelID) {
+		_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content: msg.Content,
+			Embeds:  []*discordgo.MessageEmbed{msg.Embed},
+			Files:   msg.Files,
+		})
+		if err == nil {
+			return nil
+		}
+		slog.Warn("embed nie przeszedł, wysyłam tekst", "channel", channelID, "error", err)
+		recordError("discord")
+		if !rewindFiles(msg.Files) {
+			return err
+		}
+	}
+
+	content := msg.Fallback
+	if msg.Content != "" {
+		content = msg.Content + "\n" + content
+	}
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: content,
+		Files:   msg.Files,
+	})
+	return err
+}
+
+func rewindFiles(files []*discordgo.File) bool {
+	for _, f := range files {
+		seeker, ok := f.Reader.(io.Seeker)
+		if !ok {
+			return false
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func quoteMessage(lang, title, quote string) richMessage {
+	count := len(quotes())
+	return richMessage{
+		Embed: &discordgo.MessageEmbed{
+			Type:        discordgo.EmbedTypeRich,
+			Title:       strings.ReplaceAll(title, "**", ""),
+			Description: fmt.Sprintf("*%s*", quote),
+			Color:       embedColorQuote,
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: trn(lang, "quote.footer", count, count),
+			},
+			Timestamp: time.Now().Format(time.RFC3339),
+		},
+		Fallback: fmt.Sprintf("%s\n\n*%s*", title, quote),
+	}
+}
+
+func weatherMessage(lang string, forecasts []locationForecast) richMessage {
+	title := tr(lang, "weather.tomorrow_title")
+	var b strings.Builder
+	b.WriteString("**" + title + "**")
+	fields := make([]*discordgo.MessageEmbedField, 0, len(forecasts))
+	worst := severityNone
+	for _, f := range forecasts {
+		cond := weatherCodeInfo(f.Code)
+		if cond.Severity > worst {
+			worst = cond.Severity
+		}
+		u := f.Units
+		b.WriteString("\n" + tr(lang, "weather.line", cond.Emoji, f.Name, weatherDescription(lang, f.Code),
+			f.TempMin, f.TempMax, u.tempSymbol(), f.Precip, u.precipSymbol(), f.PrecipChance,
+			f.Wind, u.windSymbol(), f.Gust, f.UVIndex, f.Sunrise, f.Sunset))
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   f.Name,
+			Value:  weatherDetails(lang, f.forecast, cond),
+			Inline: true,
+		})
+	}
+
+	footer := "Open-Meteo"
+	if len(forecasts) > 0 {
+		footer = tr(lang, "weather.footer", forecasts[0].Date)
+	}
+
+	return richMessage{
+		Embed: &discordgo.MessageEmbed{
+			Type:      discordgo.EmbedTypeRich,
+			Title:     title,
+			Color:     severityColor(worst),
+			Fields:    fields,
+			Footer:    &discordgo.MessageEmbedFooter{Text: footer},
+			Timestamp: time.Now().Format(time.RFC3339),
+		},
+		Fallback: b.String(),
+	}
+}
+
+func weatherDetails(lang string, f forecast, cond weatherCondition) string {
+	u := f.Units
+	return tr(lang, "weather.details",
+		cond.Emoji, weatherDescription(lang, f.Code), f.TempMin, f.TempMax, u.tempSymbol(), f.Precip, u.precipSymbol(), f.PrecipChance,
+		f.Wind, u.windSymbol(), f.Gust, u.windSymbol(), f.UVIndex, f.Sunrise, f.Sunset)
+}
+
+func severityColor(s weatherSeverity) int {
+	switch s {
+	case severityHigh:
+		return 0xE74C3C
+	case severityModerate:
+		return 0xE67E22
+	default:
+		return embedColorWeather
+	}
+}
+
+func weekWeatherMessage(lang string, weeks []locationWeek, chart *discordgo.File) richMessage {
+	title := tr(lang, "weather.week_title")
+	var b strings.Builder
+	b.WriteString("**" + title + "**")
+	fields := make([]*discordgo.MessageEmbedField, 0, len(weeks))
+	for _, week := range weeks {
+		var lines strings.Builder
+		for i, f := range week.Days {
+			if i > 0 {
+				lines.WriteString("\n")
+			}
+			cond := weatherCodeInfo(f.Code)
+			lines.WriteString(fmt.Sprintf("%s: %s %s, %.0f/%.0f%s, %.1f %s", forecastDayLabel(lang, f.Date), cond.Emoji, weatherDescription(lang, f.Code),
+				f.TempMin, f.TempMax, f.Units.tempSymbol(), f.Precip, f.Units.precipSymbol()))
+		}
+		b.WriteString(fmt.Sprintf("\n\n**%s**\n%s", week.Name, lines.String()))
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  week.Name,
+			Value: lines.String(),
+		})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Type:      discordgo.EmbedTypeRich,
+		Title:     title,
+		Color:     embedColorWeather,
+		Fields:    fields,
+		Footer:    &discordgo.MessageEmbedFooter{Text: "Open-Meteo"},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	msg := richMessage{Embed: embed, Fallback: b.String()}
+	if chart != nil {
+		embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + chart.Name}
+		msg.Files = []*discordgo.File{chart}
+	}
+	return msg
+}
+
+func hourlyWeatherMessage(lang, name string, hours []hourlyForecast) richMessage {
+	var table strings.Builder
+	table.WriteString("```\n" + tr(lang, "weather.hourly_header") + "\n")
+	for _, h := range hours {
+		table.WriteString(fmt.Sprintf("%s %4.0f%s %4.0f%% %3.0f %s\n", h.Time.Format("15:04"), h.Temp, h.Units.tempSymbol(), h.PrecipChance, h.WindSpeed, h.Units.windSymbol()))
+	}
+	table.WriteString("```")
+
+	title := tr(lang, "weather.hourly_title", name)
+	return richMessage{
+		Embed: &discordgo.MessageEmbed{
+			Type:        discordgo.EmbedTypeRich,
+			Title:       title,
+			Description: table.String(),
+			Color:       embedColorWeather,
+			Footer:      &discordgo.MessageEmbedFooter{Text: "Open-Meteo • " + hours[0].Time.Format("2006-01-02")},
+			Timestamp:   time.Now().Format(time.RFC3339),
+		},
+		Fallback: "**" + title + "**\n" + table.String(),
+	}
+}
+
+func gemMessage(lang string, summary gemSummary, file *discordgo.File) richMessage {
+	title := tr(lang, "gem.title")
+	var b strings.Builder
+	b.WriteString("📈 **" + title + "**")
+	fields := make([]*discordgo.MessageEmbedField, 0, len(summary.Returns))
+	for _, r := range summary.Returns {
+		b.WriteString(fmt.Sprintf("\n%s: %+0.2f%%", r.Ticker, r.Return))
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   r.Ticker,
+			Value:  fmt.Sprintf("%+0.2f%%", r.Return),
+			Inline: true,
+		})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Type:      discordgo.EmbedTypeRich,
+		Title:     "📈 " + title,
+		Color:     embedColorGem,
+		Fields:    fields,
+		Footer:    &discordgo.MessageEmbedFooter{Text: tr(lang, "gem.footer") + currencySuffix(summary.Currency)},
+		Timestamp: summary.Generated.Format(time.RFC3339),
+	}
+	if leader, ok := summary.leader(); ok {
+		embed.Description = tr(lang, "gem.leader", leader.Ticker, leader.Return)
+		if c, ok := gemColors[leader.Ticker]; ok {
+			embed.Color = int(c.R)<<16 | int(c.G)<<8 | int(c.B)
+		}
+	}
+
+	if summary.Holding != "" {
+		signal := tr(lang, "gem.signal", summary.Holding)
+		b.WriteString("\n" + signal)
+		if embed.Description != "" {
+			embed.Description += "\n"
+		}
+		embed.Description += signal
+	}
+	if len(summary.Missing) > 0 {
+		missing := tr(lang, "gem.missing", strings.Join(summary.Missing, ", "))
+		b.WriteString("\n" + missing)
+		if embed.Description != "" {
+			embed.Description += "\n"
+		}
+		embed.Description += missing
+	}
+
+	msg := richMessage{Embed: embed, Fallback: b.String()}
+	if file != nil {
+		// Discord pokazuje w embedzie tylko obrazy rastrowe; SVG i PDF idą jako zwykły załącznik.
+		if file.ContentType == "image/png" {
+			embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + file.Name}
+		}
+		msg.Files = []*discordgo.File{file}
+	}
+	return msg
+}
+
+// comparisonMessage opisuje wykres z !wykres: stopy zwrotu, lidera i pominięte tickery.
+func comparisonMessage(lang string, summary comparisonSummary, file *discordgo.File) richMessage {
+	title := tr(lang, "compare.title", summary.Spec.rangeText(lang))
+	var b strings.Builder
+	b.WriteString("📊 **" + title + "**")
+	fields := make([]*discordgo.MessageEmbedField, 0, len(summary.Returns))
+	for _, r := range summary.Returns {
+		b.WriteString(fmt.Sprintf("\n%s: %+0.2f%%", r.Ticker, r.Return))
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   r.Ticker,
+			Value:  fmt.Sprintf("%+0.2f%%", r.Return),
+			Inline: true,
+		})
+	}
+
+	interval := strings.ToLower(tr(lang, "compare.interval."+summary.Spec.Interval))
+	embed := &discordgo.MessageEmbed{
+		Type:      discordgo.EmbedTypeRich,
+		Title:     "📊 " + title,
+		Color:     embedColorGem,
+		Fields:    fields,
+		Footer:    &discordgo.MessageEmbedFooter{Text: tr(lang, "compare.footer", interval) + currencySuffix(summary.Currency)},
+		Timestamp: summary.Generated.Format(time.RFC3339),
+	}
+	if leader, ok := bestReturn(summary.Returns); ok && len(summary.Returns) > 1 {
+		embed.Description = tr(lang, "gem.leader", leader.Ticker, leader.Return)
+	}
+	if len(summary.Missing) > 0 {
+		missing := tr(lang, "gem.missing", strings.Join(summary.Missing, ", "))
+		b.WriteString("\n" + missing)
+		if embed.Description != "" {
+			embed.Description += "\n"
+		}
+		embed.Description += missing
+	}
+
+	msg := richMessage{Embed: embed, Fallback: b.String()}
+	if file != nil {
+		if file.ContentType == "image/png" {
+			embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + file.Name}
+		}
+		msg.Files = []*discordgo.File{file}
+	}
+	return msg
+}
+
+// currencySuffix dopisuje do stopki walutę, w której liczone są stopy zwrotu.
+func currencySuffix(currency string) string {
+	if currency == "" {
+		return ""
+	}
+	return " • " + currency
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"io"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+type locationWeek struct {
+	Name string
+	Days []forecast
+}
+
+var weatherPalette = []color.RGBA{
+	hexColor("E67E22"),
+	hexColor("3498DB"),
+	hexColor("2ECC71"),
+	hexColor("9B59B6"),
+	hexColor("E74C3C"),
+	hexColor("1ABC9C"),
+}
+
+func forecastDayLabel(lang, date string) string {
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return fmt.Sprintf("%s %s", tr(lang, fmt.Sprintf("weekday.short.%d", d.Weekday())), d.Format("02.01"))
+}
+
+// weatherTargets zwraca miejscowość z argumentu albo wszystkie z konfiguracji.
+func weatherTargets(ctx context.Context, name string) ([]WeatherLocation, error) {
+	if name == "" {
+		locations := weatherLocations()
+		if len(locations) == 0 {
+			return nil, fmt.Errorf("brak lokalizacji pogodowych")
+		}
+		return locations, nil
+	}
+	loc, err := resolveWeatherLocation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return []WeatherLocation{loc}, nil
+}
+
+func handleWeekForecast(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, lang, name string, units WeatherUnits) {
+	locations, err := weatherTargets(ctx, name)
+	if err != nil {
+		msgLogger(m).Warn("nie znaleziono lokalizacji", "command", "!pogoda 7d", "error", err)
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.location_unknown"))
+		return
+	}
+
+	weeks := make([]locationWeek, 0, len(locations))
+	for _, loc := range locations {
+		days, err := weatherAPI.fetchDailyForecast(ctx, loc.Latitude, loc.Longitude, 7, units)
+		if err != nil {
+			msgLogger(m).Error("błąd prognozy", "command", "!pogoda 7d", "location", loc.Name, "error", err)
+			recordError("weather")
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.fetch_failed"))
+			return
+		}
+		weeks = append(weeks, locationWeek{Name: loc.Name, Days: days})
+	}
+
+	var buf bytes.Buffer
+	var file *discordgo.File
+	if err := renderWeekChart(&buf, lang, weeks); err != nil {
+		msgLogger(m).Error("błąd wykresu pogody", "command", "!pogoda 7d", "error", err)
+	} else {
+		file = &discordgo.File{
+			Name:        "pogoda_7d.png",
+			ContentType: "image/png",
+			Reader:      bytes.NewReader(buf.Bytes()),
+		}
+	}
+	sendRich(s, m.ChannelID, weekWeatherMessage(lang, weeks, file))
+}
+
+func handleHourlyForecast(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, lang, name string, units WeatherUnits) {
+	locations, err := weatherTargets(ctx, name)
+	if err != nil {
+		msgLogger(m).Warn("nie znaleziono lokalizacji", "command", "!pogoda godzinowo", "error", err)
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.location_unknown"))
+		return
+	}
+	loc := locations[0]
+
+	hours, err := weatherAPI.fetchTomorrowHourly(ctx, loc.Latitude, loc.Longitude, units)
+	if err != nil {
+		msgLogger(m).Error("błąd prognozy", "command", "!pogoda godzinowo", "location", loc.Name, "error", err)
+		recordError("weather")
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.fetch_failed"))
+		return
+	}
+	sendRich(s, m.ChannelID, hourlyWeatherMessage(lang, loc.Name, hours))
+}
+
+func renderWeekChart(w io.Writer, lang string, weeks []locationWeek) error {
+	if len(weeks) == 0 || len(weeks[0].Days) == 0 {
+		return trError("chart.no_data")
+	}
+
+	p := plot.New()
+	p.Title.Text = tr(lang, "weather.week_chart_title")
+	p.Y.Label.Text = weeks[0].Days[0].Units.tempSymbol()
+	p.X.Min = -0.3
+	p.X.Max = float64(len(weeks[0].Days)) - 0.7
+	p.X.Tick.Marker = dayTicks{lang: lang, days: weeks[0].Days}
+	p.Add(plotter.NewGrid())
+
+	for i, week := range weeks {
+		maxPts := make(plotter.XYs, len(week.Days))
+		minPts := make(plotter.XYs, len(week.Days))
+		for d, f := range week.Days {
+			maxPts[d] = plotter.XY{X: float64(d), Y: f.TempMax}
+			minPts[d] = plotter.XY{X: float64(d), Y: f.TempMin}
+		}
+		c := weatherPalette[i%len(weatherPalette)]
+
+		maxLine, err := plotter.NewLine(maxPts)
+		if err != nil {
+			return err
+		}
+		maxLine.Color = c
+		maxLine.Width = vg.Points(1.5)
+
+		minLine, err := plotter.NewLine(minPts)
+		if err != nil {
+			return err
+		}
+		minLine.Color = c
+		minLine.Width = vg.Points(1)
+		minLine.Dashes = []vg.Length{vg.Points(4), vg.Points(3)}
+
+		p.Add(maxLine, minLine)
+		p.Legend.Add(week.Name, maxLine)
+	}
+	p.Legend.Top = true
+
+	wt, err := p.WriterTo(8*vg.Inch, 4*vg.Inch, "png")
+	if err != nil {
+		return err
+	}
+	_, err = wt.WriteTo(w)
+	return err
+}
+
+type dayTicks struct {
+	lang string
+	days []forecast
+}
+
+func (d dayTicks) Ticks(min, max float64) []plot.Tick {
+	ticks := make([]plot.Tick, 0, len(d.days))
+	for i, f := range d.days {
+		if float64(i) < min || float64(i) > max {
+			continue
+		}
+		ticks = append(ticks, plot.Tick{Value: float64(i), Label: forecastDayLabel(d.lang, f.Date)})
+	}
+	return ticks
+}
@@ -1,11 +1,11 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
-	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -15,7 +15,6 @@ import (
 	"image/color"
 
 	"gonum.org/v1/plot"
-	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/text"
 	"gonum.org/v1/plot/vg"
 	"gonum.org/v1/plot/vg/draw"
@@ -23,6 +22,11 @@ import (
 
 var gemTickers = []string{"EIMI.L", "CNDX.L", "CBU0.L", "IB01.L"}
 
+const (
+	// gemGenerateTimeout ogranicza całe generowanie wykresu z komendy lub zadania.
+	gemGenerateTimeout = 90 * time.Second
+)
+
 var gemColors = map[string]color.RGBA{
 	"EIMI.L": hexColor("0000FF"),
 	"CNDX.L": hexColor("FFA500"),
@@ -30,123 +34,107 @@ var gemColors = map[string]color.RGBA{
 	"IB01.L": hexColor("FF0000"),
 }
 
-type yahooChartResponse struct {
-	Chart struct {
-		Result []struct {
-			Timestamp  []int64 `json:"timestamp"`
-			Indicators struct {
-				Quote []struct {
-					Close []*float64 `json:"close"`
-				} `json:"quote"`
-			} `json:"indicators"`
-		} `json:"result"`
-		Error interface{} `json:"error"`
-	} `json:"chart"`
+type tickerReturn struct {
+	Ticker string
+	Return float64
 }
 
-func generateGemChart(outputPath string) error {
-	loc, err := time.LoadLocation("Europe/Warsaw")
-	if err != nil {
-		return err
-	}
-
-	end := time.Now().In(loc)
-	start := end.AddDate(-1, 0, 0)
-
-	client := &http.Client{Timeout: 20 * time.Second}
-	seriesByTicker := make(map[string]map[int64]float64, len(gemTickers))
-	baseTimestamps := []int64{}
+type gemSummary struct {
+	Generated time.Time
+	Returns   []tickerReturn
+	// Missing to tickery pominięte przy częściowych wynikach (gem_partial_results).
+	Missing []string
+	// Holding to aktywo wskazane przez regułę GEM na ostatni koniec miesiąca.
+	Holding string
+	// Currency to waluta, w której liczone są stopy zwrotu.
+	Currency string
+}
 
-	type fetchResult struct {
-		ticker string
-		ts     []int64
-		vals   []float64
-		err    error
-	}
+func (g gemSummary) leader() (tickerReturn, bool) {
+	return bestReturn(g.Returns)
+}
 
-	results := make(chan fetchResult, len(gemTickers))
-	for _, ticker := range gemTickers {
-		go func(t string) {
-			ts, vals, fetchErr := fetchYahooSeries(client, t, start, end)
-			results <- fetchResult{ticker: t, ts: ts, vals: vals, err: fetchErr}
-		}(ticker)
+// bestReturn zwraca ticker z najwyższą stopą zwrotu.
+func bestReturn(returns []tickerReturn) (tickerReturn, bool) {
+	if len(returns) == 0 {
+		return tickerReturn{}, false
 	}
-
-	for i := 0; i < len(gemTickers); i++ {
-		res := <-results
-		if res.err != nil {
-			return res.err
-		}
-		if len(baseTimestamps) == 0 {
-			baseTimestamps = res.ts
-		}
-		points := make(map[int64]float64, len(res.ts))
-		for idx, t := range res.ts {
-			points[t] = res.vals[idx]
+	best := returns[0]
+	for _, r := range returns[1:] {
+		if r.Return > best.Return {
+			best = r
 		}
-		seriesByTicker[res.ticker] = points
 	}
+	return best, true
+}
 
-	if len(baseTimestamps) == 0 {
-		return fmt.Errorf("brak danych do wykresu")
+// generateGemChart zapisuje wykres do pliku w formacie zgodnym z rozszerzeniem
+// (png, svg, pdf); używane w trybie CLI (`./main gem plik.png`).
+func generateGemChart(ctx context.Context, outputPath, lang string) (gemSummary, error) {
+	if err := ensureDir(outputPath); err != nil {
+		return gemSummary{}, err
 	}
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return gemSummary{}, err
+	}
+	summary, err := renderGemChart(ctx, f, lang, chartOptionsForPath(outputPath))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(outputPath)
+		return gemSummary{}, err
+	}
+	return summary, nil
+}
 
-	sort.Slice(baseTimestamps, func(i, j int) bool { return baseTimestamps[i] < baseTimestamps[j] })
+// renderGemChart pobiera notowania i zapisuje wykres do w w formacie z opts, bez plików tymczasowych.
+func renderGemChart(ctx context.Context, w io.Writer, lang string, opts chartOptions) (gemSummary, error) {
+	loc, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		return gemSummary{}, err
+	}
 
-	times := make([]time.Time, 0, len(baseTimestamps))
-	valuesByTicker := make(map[string][]float64, len(gemTickers))
-	lastKnown := make(map[string]float64, len(gemTickers))
-	hasKnown := make(map[string]bool, len(gemTickers))
+	end := time.Now().In(loc)
+	start := end.AddDate(-1, 0, 0)
+	// momentum i sygnały GEM potrzebują dodatkowego roku historii przed początkiem wykresu
+	fetchStart := start.AddDate(-1, 0, 0)
 
-	for _, ts := range baseTimestamps {
-		times = append(times, time.Unix(ts, 0).In(loc))
-		for _, ticker := range gemTickers {
-			if v, ok := seriesByTicker[ticker][ts]; ok && !math.IsNaN(v) {
-				lastKnown[ticker] = v
-				hasKnown[ticker] = true
-			}
-			if hasKnown[ticker] {
-				valuesByTicker[ticker] = append(valuesByTicker[ticker], lastKnown[ticker])
-			} else {
-				valuesByTicker[ticker] = append(valuesByTicker[ticker], math.NaN())
-			}
-		}
+	partial := readConfig(func(c *Config) bool { return c.GemPartialResults })
+	table, missing, err := fetchPriceTable(ctx, gemTickers, fetchStart, end, "1d", partial, loc)
+	if err != nil {
+		return gemSummary{}, err
 	}
-
-	startIdx := 0
-	for i := range times {
-		ok := true
-		for _, ticker := range gemTickers {
-			if math.IsNaN(valuesByTicker[ticker][i]) {
-				ok = false
-				break
-			}
-		}
-		if ok {
-			startIdx = i
-			break
-		}
+	currency := chartCurrency(opts)
+	table, unconverted, err := convertPriceTable(ctx, table, currency, fetchStart, end, partial, loc)
+	if err != nil {
+		return gemSummary{}, err
 	}
+	missing = append(missing, unconverted...)
+	tickers, times, valuesByTicker := table.Tickers, table.Times, table.Values
 
+	startIdx := table.firstComplete(start)
 	if startIdx >= len(times) {
-		return fmt.Errorf("brak kompletnych danych do wykresu")
+		return gemSummary{}, trError("chart.incomplete_data")
 	}
 
+	fullTimes := times
 	times = times[startIdx:]
-	returnsByTicker := make(map[string][]float64, len(gemTickers))
+	returnsByTicker := make(map[string][]float64, len(tickers))
 	maxValue := -math.MaxFloat64
 
-	for _, ticker := range gemTickers {
+	for _, ticker := range tickers {
 		series := valuesByTicker[ticker][startIdx:]
 		base := series[0]
 		if base == 0 {
-			return fmt.Errorf("wartość bazowa dla %s równa zero", ticker)
+			return gemSummary{}, trError("gem.err.zero_base", ticker)
 		}
 		ret := make([]float64, len(series))
 		for i, v := range series {
 			val := (v/base - 1) * 100
 			if math.IsNaN(val) || math.IsInf(val, 0) {
-				return fmt.Errorf("nieprawidłowe dane zwrotu dla %s", ticker)
+				return gemSummary{}, trError("gem.err.bad_return", ticker)
 			}
 			ret[i] = val
 			if val > maxValue {
@@ -157,7 +145,7 @@ func generateGemChart(outputPath string) error {
 	}
 
 	if maxValue == -math.MaxFloat64 || math.IsNaN(maxValue) || math.IsInf(maxValue, 0) {
-		return fmt.Errorf("brak danych do wykresu")
+		return gemSummary{}, trError("chart.no_data")
 	}
 
 	yMin := -25.0
@@ -183,156 +171,95 @@ func generateGemChart(outputPath string) error {
 	}
 
 	dateStr := end.Format("02 Jan 2006 15:04 MST")
-	title := fmt.Sprintf("Porównanie ETF - 1 rok                    %s               ", dateStr)
+	title := fmt.Sprintf("%s (%s)                    %s               ", tr(lang, "gem.title"), currency, dateStr)
 
-	p := plot.New()
-	p.Title.Text = title
-	p.X.Label.Text = "Interwał Miesięczny"
-	p.Y.Label.Text = ""
-	p.X.Tick.Marker = monthTicks{Loc: loc, Format: "Jan 2006"}
-	p.Y.Tick.Marker = percentTicks{}
-	p.Y.Min = yMin
-	p.Y.Max = yMax
-	p.Add(plotter.NewGrid())
-
-	rightTickStyle := p.Y.Tick.Label
-	rightLabelStyle := rightTickStyle
-	rightTickStyle.XAlign = draw.XLeft
-	rightLabelStyle.XAlign = draw.XLeft
-	axisLineStyle := draw.LineStyle{
-		Color: color.Black,
-		Width: vg.Points(0.5),
-	}
-	tickLineStyle := axisLineStyle
-	p.Y.Tick.Label.Font.Size = 0
-	p.Y.Tick.Label.Color = color.Transparent
-	p.Y.Tick.Length = 0
-	p.Y.Tick.LineStyle.Width = 0
-	p.Y.LineStyle.Width = 0
+	theme, ok := chartThemes[opts.Theme]
+	if !ok {
+		theme = chartThemes[defaultChartTheme]
+	}
 
 	xMin := float64(times[0].Unix())
-	xMax := float64(times[len(times)-1].Unix())
-	xPad := float64(45 * 24 * 3600)
+	xMax := float64(times[len(times)-1].Unix()) + float64(45*24*3600)
 
-	p.X.Min = xMin
-	p.X.Max = xMax + xPad
+	colors := theme.seriesColors(tickers)
+	holdings, switches, holding := gemSignals(fullTimes, valuesByTicker, startIdx)
 
-	seriesLabels := make([]seriesLabel, 0, len(gemTickers))
-	for _, ticker := range gemTickers {
+	p := newChartPanel(theme, loc, xMin, xMax, yMin, yMax)
+	p.Title.Text = title
+	p.Add(newGemSignalMarkers(theme, p, holdings, switches, true))
+	for _, ticker := range tickers {
 		series := returnsByTicker[ticker]
-		if len(series) == 0 {
-			continue
-		}
-		pts := make(plotter.XYs, len(times))
-		for i := range times {
-			pts[i].X = float64(times[i].Unix())
-			pts[i].Y = series[i]
-		}
-		line, err := plotter.NewLine(pts)
+		line, err := seriesLine(theme, colors[ticker], times, series)
 		if err != nil {
-			return err
+			return gemSummary{}, err
 		}
-		line.Color = gemColors[ticker]
-		line.Width = vg.Points(1.5)
 		p.Add(line)
-		legendLabel := fmt.Sprintf("%s: %+0.2f%%", ticker, series[len(series)-1])
-		p.Legend.Add(legendLabel, line)
-		seriesLabels = append(seriesLabels, seriesLabel{
-			Text:  fmt.Sprintf("%s %+0.2f%%", ticker, series[len(series)-1]),
-			Value: series[len(series)-1],
-			Color: gemColors[ticker],
-		})
+		p.Legend.Add(fmt.Sprintf("%s: %+0.2f%%", ticker, series[len(series)-1]), line)
 	}
-
 	p.Legend.Top = true
 	p.Legend.Left = true
 	p.Legend.XOffs = vg.Points(6)
 	p.Legend.YOffs = vg.Points(-6)
-	p.Add(rightSideAnnotations{
-		Ticker:        percentTicks{},
-		TickStyle:     rightTickStyle,
-		LabelStyle:    rightLabelStyle,
-		TickLength:    vg.Points(4),
-		TickPadding:   vg.Points(6),
-		LabelSpacing:  vg.Points(20),
-		Gap:           vg.Points(2),
-		AxisLineStyle: axisLineStyle,
-		TickLineStyle: tickLineStyle,
-		Labels:        seriesLabels,
-	})
-
-	if err := ensureDir(outputPath); err != nil {
-		return err
-	}
-
-	fmt.Println("\n============================================================")
-	fmt.Println("STOPY ZWROTU - 1 ROK:")
-	fmt.Println("============================================================")
-	for _, ticker := range gemTickers {
-		series := returnsByTicker[ticker]
-		if len(series) == 0 {
-			continue
+	p.Add(rightAxis(theme, p, panelLabels(colors, tickers, returnsByTicker, returnLabelFormat)))
+
+	panels := []chartPanel{{Plot: p, Weight: gemMainPanelWeight}}
+	addPanel := func(titleKey string, series map[string][]float64, minSpan float64) error {
+		lo, hi := seriesRange(series, 0, minSpan)
+		panel := newChartPanel(theme, loc, xMin, xMax, lo, hi)
+		panel.Title.Text = tr(lang, titleKey)
+		panel.Add(newGemSignalMarkers(theme, panel, nil, switches, false))
+		for _, ticker := range tickers {
+			line, err := seriesLine(theme, colors[ticker], times, series[ticker])
+			if err != nil {
+				return err
+			}
+			panel.Add(line)
 		}
-		fmt.Printf("%-10s: %+7.2f%%\n", ticker, series[len(series)-1])
-	}
-	fmt.Println("============================================================\n")
-
-	return p.Save(12*vg.Inch, 6*vg.Inch, outputPath)
-}
-
-func fetchYahooSeries(client *http.Client, ticker string, start, end time.Time) ([]int64, []float64, error) {
-	requestURL := fmt.Sprintf(
-		"https://query2.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=1d&events=history&includeAdjustedClose=true",
-		url.PathEscape(ticker),
-		start.Unix(),
-		end.Unix(),
-	)
-
-	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-	req.Header.Set("User-Agent", "zlotemyslibot")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, nil, fmt.Errorf("yahoo status %d dla %s", resp.StatusCode, ticker)
+		panel.Add(rightAxis(theme, panel, panelLabels(colors, tickers, series, returnLabelFormat)))
+		panels = append(panels, chartPanel{Plot: panel, Weight: gemSidePanelWeight})
+		return nil
 	}
 
-	var payload yahooChartResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, nil, err
+	drawdowns := make(map[string][]float64, len(tickers))
+	for _, ticker := range tickers {
+		drawdowns[ticker] = drawdownSeries(valuesByTicker[ticker][startIdx:])
 	}
-
-	if len(payload.Chart.Result) == 0 {
-		return nil, nil, fmt.Errorf("brak wyników dla %s", ticker)
+	if err := addPanel("gem.drawdown_title", drawdowns, 10); err != nil {
+		return gemSummary{}, err
 	}
 
-	result := payload.Chart.Result[0]
-	if len(result.Timestamp) == 0 || len(result.Indicators.Quote) == 0 {
-		return nil, nil, fmt.Errorf("brak danych cenowych dla %s", ticker)
+	if opts.Momentum {
+		momentum := make(map[string][]float64, len(tickers))
+		for _, ticker := range tickers {
+			momentum[ticker] = momentumSeries(fullTimes, valuesByTicker[ticker], startIdx)
+		}
+		if err := addPanel("gem.momentum_title", momentum, 10); err != nil {
+			return gemSummary{}, err
+		}
 	}
 
-	closings := result.Indicators.Quote[0].Close
-	if len(closings) != len(result.Timestamp) {
-		return nil, nil, fmt.Errorf("niezgodna długość danych dla %s", ticker)
+	for _, panel := range panels[:len(panels)-1] {
+		hideXLabels(panel.Plot)
 	}
+	panels[len(panels)-1].Plot.X.Label.Text = tr(lang, "gem.x_label")
 
-	values := make([]float64, len(result.Timestamp))
-	for i, v := range closings {
-		if v == nil || math.IsNaN(*v) {
-			values[i] = math.NaN()
-		} else {
-			values[i] = *v
+	summary := gemSummary{Generated: end, Missing: missing, Holding: holding, Currency: currency}
+	returnAttrs := make([]any, 0, len(tickers))
+	for _, ticker := range tickers {
+		series := returnsByTicker[ticker]
+		if len(series) == 0 {
+			continue
 		}
+		last := series[len(series)-1]
+		summary.Returns = append(summary.Returns, tickerReturn{Ticker: ticker, Return: last})
+		returnAttrs = append(returnAttrs, slog.String(ticker, fmt.Sprintf("%+.2f%%", last)))
 	}
+	slog.Debug("stopy zwrotu - 1 rok", slog.Group("returns", returnAttrs...))
 
-	return result.Timestamp, values, nil
+	if err := writePanels(w, panels, opts); err != nil {
+		return gemSummary{}, err
+	}
+	return summary, nil
 }
 
 type percentTicks struct{}
@@ -359,8 +286,13 @@ func (m monthTicks) Ticks(min, max float64) []plot.Tick {
 	}
 	minTime := time.Unix(int64(min), 0).In(loc)
 	maxTime := time.Unix(int64(max), 0).In(loc)
+	// przy krótkich zakresach podziałka miesięczna dałaby jedną kreskę albo żadną
+	if maxTime.Sub(minTime) < 75*24*time.Hour {
+		return weekTicks(minTime, maxTime, loc)
+	}
+	step := monthTickStep(minTime, maxTime)
 	start := time.Date(minTime.Year(), minTime.Month(), 1, 0, 0, 0, 0, loc)
-	if start.Before(minTime) {
+	for start.Before(minTime) || (int(start.Month())-1)%step != 0 {
 		start = start.AddDate(0, 1, 0)
 	}
 	format := m.Format
@@ -368,7 +300,7 @@ func (m monthTicks) Ticks(min, max float64) []plot.Tick {
 		format = "Jan 2006"
 	}
 	ticks := []plot.Tick{}
-	for t := start; !t.After(maxTime); t = t.AddDate(0, 1, 0) {
+	for t := start; !t.After(maxTime); t = t.AddDate(0, step, 0) {
 		ticks = append(ticks, plot.Tick{
 			Value: float64(t.Unix()),
 			Label: t.Format(format),
@@ -377,6 +309,38 @@ func (m monthTicks) Ticks(min, max float64) []plot.Tick {
 	return ticks
 }
 
+// weekTicks stawia podziałkę w poniedziałki, z podpisem dnia i miesiąca.
+func weekTicks(minTime, maxTime time.Time, loc *time.Location) []plot.Tick {
+	start := time.Date(minTime.Year(), minTime.Month(), minTime.Day(), 0, 0, 0, 0, loc)
+	for start.Before(minTime) || start.Weekday() != time.Monday {
+		start = start.AddDate(0, 0, 1)
+	}
+	ticks := []plot.Tick{}
+	for t := start; !t.After(maxTime); t = t.AddDate(0, 0, 7) {
+		ticks = append(ticks, plot.Tick{Value: float64(t.Unix()), Label: t.Format("02 Jan")})
+	}
+	return ticks
+}
+
+// monthTickStep dobiera co ile miesięcy stawiać podziałkę, żeby przy dłuższych
+// zakresach podpisy się nie nakładały (rok to nadal co miesiąc).
+func monthTickStep(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	switch {
+	case months <= 14:
+		return 1
+	case months <= 28:
+		return 2
+	case months <= 48:
+		return 3
+	case months <= 96:
+		return 6
+	case months <= 180:
+		return 12
+	}
+	return 24
+}
+
 type seriesLabel struct {
 	Text  string
 	Value float64
@@ -530,6 +494,16 @@ func placeLabelY(desired vg.Length, text string, style text.Style, minY, maxY, g
 			return y
 		}
 	}
+	// W niskich panelach może zabraknąć miejsca między podziałkami;
+	// wtedy ważniejsze jest, żeby etykiety serii nie nachodziły na siebie.
+	for _, y := range candidates {
+		if y < minAllowed || y > maxAllowed {
+			continue
+		}
+		if !overlapsAny(labelSpan(y, text, style, gap/2), placed) {
+			return y
+		}
+	}
 
 	if desired < minAllowed {
 		return minAllowed
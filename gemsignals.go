@@ -0,0 +1,169 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Reguła GEM: na koniec miesiąca trzymamy akcje z najwyższym 12-miesięcznym
+// momentum, o ile bije ono bony skarbowe; w przeciwnym razie obligacje.
+var (
+	gemEquityTickers = []string{"EIMI.L", "CNDX.L"}
+	gemCashTicker    = "IB01.L"
+	gemBondTicker    = "CBU0.L"
+)
+
+// gemHolding to okres trzymania jednego aktywa (oś X w sekundach Unix).
+type gemHolding struct {
+	From, To float64
+	Ticker   string
+}
+
+// gemSwitch to data decyzji, w której strategia zmieniła aktywo.
+type gemSwitch struct {
+	At     float64
+	Ticker string
+}
+
+// gemDecision wybiera aktywo według reguły GEM dla indeksu i; false, gdy brakuje danych.
+func gemDecision(momentum map[string][]float64, i int) (string, bool) {
+	cash, ok := momentum[gemCashTicker]
+	if !ok || !isFinite(cash[i]) {
+		return "", false
+	}
+	best, bestMom := "", math.Inf(-1)
+	for _, ticker := range gemEquityTickers {
+		series, ok := momentum[ticker]
+		if !ok || !isFinite(series[i]) {
+			continue
+		}
+		if series[i] > bestMom {
+			best, bestMom = ticker, series[i]
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	if bestMom > cash[i] {
+		return best, true
+	}
+	if _, ok := momentum[gemBondTicker]; ok {
+		return gemBondTicker, true
+	}
+	return gemCashTicker, true
+}
+
+// isMonthEnd mówi, czy i to ostatnia sesja miesiąca. Bieżący, niezamknięty miesiąc się nie liczy.
+func isMonthEnd(times []time.Time, i int) bool {
+	return i+1 < len(times) && times[i+1].Month() != times[i].Month()
+}
+
+// gemSignals wyznacza okresy trzymania i zmiany sygnału w oknie od indeksu from.
+// Pierwszy okres zaczyna się od decyzji z końca miesiąca sprzed okna, jeśli jest dostępna.
+// current to aktywo z ostatniej decyzji.
+func gemSignals(times []time.Time, values map[string][]float64, from int) (holdings []gemHolding, switches []gemSwitch, current string) {
+	momentum := make(map[string][]float64, len(values))
+	for ticker, series := range values {
+		momentum[ticker] = momentumSeries(times, series, 0)
+	}
+
+	windowStart := float64(times[from].Unix())
+	windowEnd := float64(times[len(times)-1].Unix())
+	for i := range times {
+		if !isMonthEnd(times, i) {
+			continue
+		}
+		ticker, ok := gemDecision(momentum, i)
+		if !ok {
+			continue
+		}
+		at := float64(times[i].Unix())
+		if current != "" && ticker != current && i >= from {
+			switches = append(switches, gemSwitch{At: at, Ticker: ticker})
+		}
+		if n := len(holdings); n > 0 {
+			holdings[n-1].To = at
+		}
+		holdings = append(holdings, gemHolding{From: math.Max(at, windowStart), To: windowEnd, Ticker: ticker})
+		current = ticker
+	}
+
+	// okresy zakończone przed oknem nie są rysowane
+	visible := holdings[:0]
+	for _, h := range holdings {
+		if h.To > windowStart {
+			visible = append(visible, h)
+		}
+	}
+	return visible, switches, current
+}
+
+// gemSignalMarkers cieniuje tło kolorem trzymanego aktywa i rysuje pionowe
+// znaczniki w dniach zmiany sygnału, podpisane nowym aktywem.
+type gemSignalMarkers struct {
+	Holdings   []gemHolding
+	Switches   []gemSwitch
+	Theme      chartTheme
+	ShadeAlpha uint8
+	LineStyle  draw.LineStyle
+	// LabelStyle z zerowym rozmiarem czcionki wyłącza podpisy (panele pomocnicze).
+	LabelStyle text.Style
+}
+
+func newGemSignalMarkers(theme chartTheme, p *plot.Plot, holdings []gemHolding, switches []gemSwitch, labels bool) gemSignalMarkers {
+	labelStyle := p.Y.Tick.Label
+	labelStyle.XAlign = draw.XLeft
+	labelStyle.YAlign = draw.YBottom
+	if !labels {
+		labelStyle.Font.Size = 0
+	}
+	return gemSignalMarkers{
+		Holdings:   holdings,
+		Switches:   switches,
+		Theme:      theme,
+		ShadeAlpha: 0x26,
+		LineStyle: draw.LineStyle{
+			Color:  theme.Text,
+			Width:  vg.Points(0.75),
+			Dashes: []vg.Length{vg.Points(4), vg.Points(3)},
+		},
+		LabelStyle: labelStyle,
+	}
+}
+
+func (m gemSignalMarkers) shade(ticker string) color.Color {
+	c := color.NRGBAModel.Convert(m.Theme.seriesColor(ticker)).(color.NRGBA)
+	c.A = m.ShadeAlpha
+	return c
+}
+
+func (m gemSignalMarkers) Plot(c draw.Canvas, p *plot.Plot) {
+	for _, h := range m.Holdings {
+		x0 := c.X(p.X.Norm(h.From))
+		x1 := c.X(p.X.Norm(h.To))
+		if x1 <= x0 {
+			continue
+		}
+		rect := vg.Rectangle{Min: vg.Point{X: x0, Y: c.Min.Y}, Max: vg.Point{X: x1, Y: c.Max.Y}}
+		c.SetColor(m.shade(h.Ticker))
+		c.Fill(rect.Path())
+	}
+
+	for _, s := range m.Switches {
+		x := c.X(p.X.Norm(s.At))
+		c.StrokeLine2(m.LineStyle, x, c.Min.Y, x, c.Max.Y)
+		if m.LabelStyle.Font.Size == 0 {
+			continue
+		}
+		sty := m.LabelStyle
+		sty.Color = m.Theme.seriesColor(s.Ticker)
+		// podpis na dole, bo legenda zajmuje lewy górny róg
+		c.FillText(sty, vg.Point{X: x + vg.Points(3), Y: c.Min.Y + vg.Points(3)}, "→ "+s.Ticker)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// syntheticGemTable buduje notowania GEM z dwoma sesjami w miesiącu (1. i 15.),
+// więc momentum na koniec miesiąca m to f(m)/f(m-12)-1.
+func syntheticGemTable(months int) ([]time.Time, map[string][]float64, func(i int) int) {
+	eimi := func(m int) float64 {
+		if m <= 20 {
+			return 100 * math.Pow(1.02, float64(m))
+		}
+		return 100 * math.Pow(1.02, 20) * math.Pow(0.95, float64(m-20))
+	}
+	cndx := func(m int) float64 {
+		if m <= 24 {
+			return 100 + float64(m)
+		}
+		return 124 - 10*float64(m-24)
+	}
+	cash := func(m int) float64 { return 100 + 0.2*float64(m) }
+	bonds := func(m int) float64 { return 100 }
+
+	var times []time.Time
+	values := map[string][]float64{}
+	for m := range months {
+		for _, day := range []int{1, 15} {
+			times = append(times, time.Date(2024, time.January+time.Month(m), day, 0, 0, 0, 0, time.UTC))
+			values["EIMI.L"] = append(values["EIMI.L"], eimi(m))
+			values["CNDX.L"] = append(values["CNDX.L"], cndx(m))
+			values["IB01.L"] = append(values["IB01.L"], cash(m))
+			values["CBU0.L"] = append(values["CBU0.L"], bonds(m))
+		}
+	}
+	// indeks sesji z 15. dnia miesiąca m, czyli końca miesiąca
+	monthEnd := func(m int) int { return 2*m + 1 }
+	return times, values, monthEnd
+}
+
+func TestGemSignals(t *testing.T) {
+	times, values, monthEnd := syntheticGemTable(30)
+	from := 2 * 18 // 1 lipca 2025
+	holdings, switches, current := gemSignals(times, values, from)
+
+	expected := func(m int) string {
+		switch {
+		case m <= 21:
+			return "EIMI.L"
+		case m <= 24:
+			return "CNDX.L"
+		default:
+			return "CBU0.L"
+		}
+	}
+	unix := func(i int) float64 { return float64(times[i].Unix()) }
+
+	// pierwszy widoczny okres to decyzja z końca miesiąca 17, przycięta do początku okna
+	if len(holdings) != 12 {
+		t.Fatalf("len(holdings) = %d, want 12: %+v", len(holdings), holdings)
+	}
+	for k, h := range holdings {
+		m := 17 + k
+		if h.Ticker != expected(m) {
+			t.Errorf("okres %d (miesiąc %d): %s, want %s", k, m, h.Ticker, expected(m))
+		}
+		wantFrom := unix(monthEnd(m))
+		if k == 0 {
+			wantFrom = unix(from)
+		}
+		wantTo := unix(len(times) - 1)
+		if m < 28 {
+			wantTo = unix(monthEnd(m + 1))
+		}
+		if h.From != wantFrom || h.To != wantTo {
+			t.Errorf("okres %d: %v-%v, want %v-%v", k, h.From, h.To, wantFrom, wantTo)
+		}
+	}
+
+	wantSwitches := []gemSwitch{
+		{At: unix(monthEnd(22)), Ticker: "CNDX.L"},
+		{At: unix(monthEnd(25)), Ticker: "CBU0.L"},
+	}
+	if len(switches) != len(wantSwitches) {
+		t.Fatalf("switches = %+v, want %+v", switches, wantSwitches)
+	}
+	for i, sw := range switches {
+		if sw != wantSwitches[i] {
+			t.Errorf("switch %d = %+v (%s), want %+v (%s)", i, sw, time.Unix(int64(sw.At), 0).UTC().Format("2006-01-02"),
+				wantSwitches[i], time.Unix(int64(wantSwitches[i].At), 0).UTC().Format("2006-01-02"))
+		}
+	}
+	if current != "CBU0.L" {
+		t.Errorf("current = %s, want CBU0.L", current)
+	}
+
+	// zmiana sprzed okna nie jest znacznikiem
+	_, switches, _ = gemSignals(times, values, monthEnd(23))
+	if len(switches) != 1 || switches[0].Ticker != "CBU0.L" {
+		t.Errorf("switches od miesiąca 23 = %+v, want tylko CBU0.L", switches)
+	}
+}
+
+func TestGemDecision(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name     string
+		momentum map[string][]float64
+		want     string
+		ok       bool
+	}{
+		{"akcje biją gotówkę", map[string][]float64{"EIMI.L": {8}, "CNDX.L": {12}, "IB01.L": {3}, "CBU0.L": {1}}, "CNDX.L", true},
+		{"obligacje, gdy akcje słabsze od gotówki", map[string][]float64{"EIMI.L": {2}, "CNDX.L": {-5}, "IB01.L": {3}, "CBU0.L": {1}}, "CBU0.L", true},
+		{"gotówka bez obligacji", map[string][]float64{"EIMI.L": {2}, "CNDX.L": {-5}, "IB01.L": {3}}, "IB01.L", true},
+		{"brak momentum jednego ETF", map[string][]float64{"EIMI.L": {nan}, "CNDX.L": {5}, "IB01.L": {3}}, "CNDX.L", true},
+		{"brak gotówki", map[string][]float64{"EIMI.L": {8}, "CNDX.L": {12}}, "", false},
+		{"brak akcji", map[string][]float64{"EIMI.L": {nan}, "CNDX.L": {nan}, "IB01.L": {3}}, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := gemDecision(tt.momentum, 0)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: gemDecision = %s, %v; want %s, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Nazwy metryk w formacie Prometheusa.
+const (
+	metricCommands    = "bot_commands_total"
+	metricErrors      = "bot_errors_total"
+	metricJobRuns     = "bot_job_runs_total"
+	metricJobDuration = "bot_job_attempt_duration_seconds"
+	metricAPIRequests = "bot_external_requests_total"
+	metricAPIDuration = "bot_external_request_duration_seconds"
+	metricGatewayUp   = "bot_gateway_connected"
+	metricCronRunning = "bot_cron_running"
+	metricUptime      = "bot_uptime_seconds"
+)
+
+var metricHelp = map[string]string{
+	metricCommands:    "Bot commands handled, by command.",
+	metricErrors:      "Errors reported to users or operators, by source.",
+	metricJobRuns:     "Scheduled job runs, by job and outcome.",
+	metricJobDuration: "Duration of a single scheduled job attempt.",
+	metricAPIRequests: "Requests to external APIs, by API and outcome.",
+	metricAPIDuration: "Latency of external API requests.",
+	metricGatewayUp:   "1 if the Discord gateway connection is up.",
+	metricCronRunning: "1 if the cron scheduler is running.",
+	metricUptime:      "Seconds since the process started.",
+}
+
+var histogramBuckets = map[string][]float64{
+	metricJobDuration: {0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	metricAPIDuration: {0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20},
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func (h *histogram) observe(v float64) {
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// metricsRegistry to minimalny rejestr liczników i histogramów bez zewnętrznych zależności.
+// Etykiety są trzymane jako gotowy tekst, np. `job="gem",outcome="success"`.
+type metricsRegistry struct {
+	mu         sync.Mutex
+	started    time.Time
+	counters   map[string]map[string]uint64
+	histograms map[string]map[string]*histogram
+}
+
+var botMetrics = newMetricsRegistry()
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		started:    time.Now(),
+		counters:   make(map[string]map[string]uint64),
+		histograms: make(map[string]map[string]*histogram),
+	}
+}
+
+func (m *metricsRegistry) inc(name, labels string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counters[name] == nil {
+		m.counters[name] = make(map[string]uint64)
+	}
+	m.counters[name][labels]++
+}
+
+func (m *metricsRegistry) observe(name, labels string, v float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.histograms[name] == nil {
+		m.histograms[name] = make(map[string]*histogram)
+	}
+	h, ok := m.histograms[name][labels]
+	if !ok {
+		buckets := histogramBuckets[name]
+		h = &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+		m.histograms[name][labels] = h
+	}
+	h.observe(v)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricLabels składa pary klucz, wartość w etykiety Prometheusa.
+func metricLabels(kv ...string) string {
+	parts := make([]string, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, kv[i], labelEscaper.Replace(kv[i+1])))
+	}
+	return strings.Join(parts, ",")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeHeader(w io.Writer, name, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, metricHelp[name], name, kind)
+}
+
+func withLabels(name, labels string) string {
+	if labels == "" {
+		return name
+	}
+	return name + "{" + labels + "}"
+}
+
+// writeTo wypisuje metryki w formacie tekstowym Prometheusa (text/plain; version=0.0.4).
+func (m *metricsRegistry) writeTo(w io.Writer, gauges map[string]float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, name := range sortedKeys(m.counters) {
+		writeHeader(w, name, "counter")
+		for _, labels := range sortedKeys(m.counters[name]) {
+			fmt.Fprintf(w, "%s %d\n", withLabels(name, labels), m.counters[name][labels])
+		}
+	}
+	for _, name := range sortedKeys(m.histograms) {
+		writeHeader(w, name, "histogram")
+		for _, labels := range sortedKeys(m.histograms[name]) {
+			h := m.histograms[name][labels]
+			sep := ""
+			if labels != "" {
+				sep = ","
+			}
+			for i, b := range h.buckets {
+				fmt.Fprintf(w, "%s_bucket{%s%sle=\"%g\"} %d\n", name, labels, sep, b, h.counts[i])
+			}
+			fmt.Fprintf(w, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+			fmt.Fprintf(w, "%s %g\n", withLabels(name+"_sum", labels), h.sum)
+			fmt.Fprintf(w, "%s %d\n", withLabels(name+"_count", labels), h.count)
+		}
+	}
+	for _, name := range sortedKeys(gauges) {
+		writeHeader(w, name, "gauge")
+		fmt.Fprintf(w, "%s %g\n", name, gauges[name])
+	}
+}
+
+func recordCommand(cmd string) {
+	botMetrics.inc(metricCommands, metricLabels("command", cmd))
+}
+
+func recordError(source string) {
+	botMetrics.inc(metricErrors, metricLabels("source", source))
+}
+
+func observeJobAttempt(job string, d time.Duration) {
+	botMetrics.observe(metricJobDuration, metricLabels("job", job), d.Seconds())
+}
+
+func countJobRun(job, outcome string) {
+	botMetrics.inc(metricJobRuns, metricLabels("job", job, "outcome", outcome))
+}
+
+// observeAPI zapisuje czas i wynik zapytania do zewnętrznego API (yahoo, open-meteo, ...).
+func observeAPI(api string, d time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	botMetrics.inc(metricAPIRequests, metricLabels("api", api, "outcome", outcome))
+	botMetrics.observe(metricAPIDuration, metricLabels("api", api), d.Seconds())
+}
+
+// gatewayConnected śledzi połączenie z gatewayem na podstawie zdarzeń discordgo.
+var gatewayConnected atomic.Bool
+
+func trackGatewayState(s *discordgo.Session) {
+	s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Connect) { gatewayConnected.Store(true) })
+	s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Ready) { gatewayConnected.Store(true) })
+	s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Resumed) { gatewayConnected.Store(true) })
+	s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) { gatewayConnected.Store(false) })
+}
+
+// readiness zwraca listę problemów; pusta oznacza gotowość.
+func readiness() []string {
+	var problems []string
+	if !gatewayConnected.Load() {
+		problems = append(problems, "discord gateway disconnected")
+	}
+	if jobScheduler == nil || !jobScheduler.isRunning() {
+		problems = append(problems, "cron not running")
+	}
+	return problems
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func newHealthMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok\n")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if problems := readiness(); len(problems) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, strings.Join(problems, "\n")+"\n")
+			return
+		}
+		io.WriteString(w, "ok\n")
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		botMetrics.writeTo(w, map[string]float64{
+			metricGatewayUp:   boolGauge(gatewayConnected.Load()),
+			metricCronRunning: boolGauge(jobScheduler != nil && jobScheduler.isRunning()),
+			metricUptime:      time.Since(botMetrics.started).Seconds(),
+		})
+	})
+	return mux
+}
+
+// startHealthServer uruchamia serwer /healthz, /readyz i /metrics, jeśli podano adres (HEALTH_ADDR).
+func startHealthServer(addr string) *http.Server {
+	if addr == "" {
+		return nil
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           newHealthMux(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("błąd serwera health", "addr", addr, "error", err)
+		}
+	}()
+	slog.Info("health i metryki uruchomione", "addr", addr)
+	return srv
+}
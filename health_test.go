@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	prevMetrics, prevScheduler, prevGateway := botMetrics, jobScheduler, gatewayConnected.Load()
+	t.Cleanup(func() {
+		botMetrics, jobScheduler = prevMetrics, prevScheduler
+		gatewayConnected.Store(prevGateway)
+	})
+	botMetrics = newMetricsRegistry()
+	jobScheduler = &scheduler{}
+	gatewayConnected.Store(false)
+
+	srv := httptest.NewServer(newHealthMux())
+	t.Cleanup(srv.Close)
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/healthz"); code != http.StatusOK || body != "ok\n" {
+		t.Errorf("/healthz = %d %q", code, body)
+	}
+
+	// gotowość wymaga połączenia z gatewayem i działającego crona
+	steps := []struct {
+		gateway, cron bool
+		code          int
+		body          string
+	}{
+		{false, false, http.StatusServiceUnavailable, "discord gateway disconnected\ncron not running\n"},
+		{true, false, http.StatusServiceUnavailable, "cron not running\n"},
+		{false, true, http.StatusServiceUnavailable, "discord gateway disconnected\n"},
+		{true, true, http.StatusOK, "ok\n"},
+	}
+	for _, step := range steps {
+		gatewayConnected.Store(step.gateway)
+		jobScheduler.started = step.cron
+		if code, body := get("/readyz"); code != step.code || body != step.body {
+			t.Errorf("/readyz (gateway %v, cron %v) = %d %q, want %d %q", step.gateway, step.cron, code, body, step.code, step.body)
+		}
+	}
+
+	countJobRun("gem", "success")
+	countJobRun("gem", "success")
+	countJobRun("cytat", "failure")
+	recordError("job")
+	recordError(`we"ird`)
+	_, metrics := get("/metrics")
+	for _, line := range []string{
+		"# TYPE bot_job_runs_total counter",
+		`bot_job_runs_total{job="cytat",outcome="failure"} 1`,
+		`bot_job_runs_total{job="gem",outcome="success"} 2`,
+		"# HELP bot_errors_total " + metricHelp[metricErrors],
+		`bot_errors_total{source="job"} 1`,
+		`bot_errors_total{source="we\"ird"} 1`,
+		"# TYPE bot_gateway_connected gauge",
+		"bot_gateway_connected 1",
+		"bot_cron_running 1",
+	} {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("/metrics bez linii %q:\n%s", line, metrics)
+		}
+	}
+
+	countJobRun("gem", "success")
+	if _, metrics := get("/metrics"); !strings.Contains(metrics, `bot_job_runs_total{job="gem",outcome="success"} 3`+"\n") {
+		t.Errorf("licznik gem nie wzrósł:\n%s", metrics)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const defaultLanguage = "pl"
+
+// catalog to tłumaczenia jednego języka. Formy mnogie są ułożone według
+// kategorii zwracanej przez pluralForm dla danego języka.
+type catalog struct {
+	messages map[string]string
+	plurals  map[string][]string
+}
+
+var catalogs = map[string]catalog{
+	"pl": catalogPL,
+	"en": catalogEN,
+}
+
+func supportedLanguages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// langFor zwraca język serwera, a poza serwerem (DM) domyślny.
+func langFor(guildID string) string {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if lang, ok := config.GuildLanguages[guildID]; ok {
+		return lang
+	}
+	return defaultLanguage
+}
+
+// channelLang ustala język kanału dla wiadomości wysyłanych bez komendy, np. z crona.
+func channelLang(s *discordgo.Session, channelID string) string {
+	return langFor(channelGuild(s, channelID))
+}
+
+// channelGuild zwraca ID serwera kanału (pusty dla DM albo nieznanego kanału).
+func channelGuild(s *discordgo.Session, channelID string) string {
+	if channelID == "" {
+		return ""
+	}
+	ch, err := s.State.Channel(channelID)
+	if err != nil {
+		ch, err = s.Channel(channelID)
+		if err != nil {
+			return ""
+		}
+	}
+	return ch.GuildID
+}
+
+// tr zwraca komunikat z katalogu języka, a w jego braku z polskiego.
+// Nieznany klucz jest zwracany bez zmian, żeby brak tłumaczenia było widać.
+func tr(lang, key string, args ...any) string {
+	msg, ok := catalogs[lang].messages[key]
+	if !ok {
+		msg, ok = catalogs[defaultLanguage].messages[key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// trn wybiera formę mnogą dla n; n nie jest dodawane do argumentów automatycznie.
+func trn(lang, key string, n int, args ...any) string {
+	forms, ok := catalogs[lang].plurals[key]
+	if !ok {
+		lang = defaultLanguage
+		forms, ok = catalogs[lang].plurals[key]
+	}
+	if !ok || len(forms) == 0 {
+		return key
+	}
+	i := pluralForm(lang, n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+	return fmt.Sprintf(forms[i], args...)
+}
+
+// pluralForm: po polsku 1 / 2-4 (bez 12-14) / reszta, po angielsku 1 / reszta.
+func pluralForm(lang string, n int) int {
+	if n < 0 {
+		n = -n
+	}
+	if n == 1 {
+		return 0
+	}
+	if lang != "pl" {
+		return 1
+	}
+	if n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14) {
+		return 1
+	}
+	return 2
+}
+
+// localizedError to błąd z kluczem katalogu, tłumaczony dopiero przy pokazaniu użytkownikowi.
+// W logach (Error) ląduje wersja polska.
+type localizedError struct {
+	Key  string
+	Args []any
+}
+
+func (e *localizedError) Error() string {
+	return tr(defaultLanguage, e.Key, e.Args...)
+}
+
+func trError(key string, args ...any) error {
+	return &localizedError{Key: key, Args: args}
+}
+
+// localizeError tłumaczy błąd z katalogu, a inne błędy zwraca w oryginale.
+func localizeError(lang string, err error) string {
+	var le *localizedError
+	if errors.As(err, &le) {
+		return tr(lang, le.Key, le.Args...)
+	}
+	return err.Error()
+}
+
+func handleLanguageCommand(s *discordgo.Session, m *discordgo.MessageCreate, arg string) {
+	lang := langFor(m.GuildID)
+	arg = strings.ToLower(strings.TrimSpace(arg))
+	if arg == "" {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "language.current", lang))
+		return
+	}
+	if _, ok := catalogs[arg]; !ok {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "language.usage", strings.Join(supportedLanguages(), "|")))
+		return
+	}
+	if m.GuildID == "" {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "language.guild_only"))
+		return
+	}
+	updateConfig(func(c *Config) {
+		if c.GuildLanguages == nil {
+			c.GuildLanguages = make(map[string]string)
+		}
+		c.GuildLanguages[m.GuildID] = arg
+	})
+	s.ChannelMessageSend(m.ChannelID, tr(arg, "language.set"))
+}
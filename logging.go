@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// setupLogger ustawia domyślny logger slog według zmiennych środowiskowych:
+// LOG_FORMAT=text|json (domyślnie text) i LOG_LEVEL=debug|info|warn|error (domyślnie info).
+// Wpisy ze standardowego pakietu log (np. z discordgo) też trafiają do tego loggera.
+func setupLogger() {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "json") {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+// msgLogger zwraca logger z kontekstem wiadomości: serwer, kanał i autor.
+func msgLogger(m *discordgo.MessageCreate) *slog.Logger {
+	return slog.With("guild", m.GuildID, "channel", m.ChannelID, "user", m.Author.ID)
+}
+
+// fatal loguje błąd i kończy proces, jak log.Fatal.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
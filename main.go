@@ -1,30 +1,34 @@
 package main
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"math/rand"
-	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
-	"github.com/robfig/cron/v3"
 )
 
 func main() {
 	godotenv.Load()
+	setupLogger()
+
+	if len(os.Args) > 1 && os.Args[1] == "gem" {
+		runGemCLI(os.Args[2:])
+		return
+	}
 
 	token := os.Getenv("DISCORD_TOKEN")
 	if token == "" {
-		log.Fatal("Brak tokena Discord! Ustaw zmienną DISCORD_TOKEN")
+		fatal("brak tokena Discord, ustaw zmienną DISCORD_TOKEN")
 	}
 
 	rand.Seed(time.Now().UnixNano()) // ✅ Losowe cytaty
@@ -33,111 +37,192 @@ func main() {
 
 	dg, err := discordgo.New("Bot " + token)
 	if err != nil {
-		log.Fatal("Błąd tworzenia sesji:", err)
+		fatal("błąd tworzenia sesji", "error", err)
 	}
 
-	dg.AddHandler(messageCreate)
+	// ctx trafia do komend i zadań; anulujemy go dopiero, gdy zamykanie przekroczy limit czasu
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gate := &commandGate{}
+	dg.AddHandler(gate.handler(ctx))
+	trackGatewayState(dg)
 	dg.Identify.Intents = discordgo.IntentsGuildMessages
 
 	// 🚀 CRON SCHEDULER zamiast tickera
-	go startCronScheduler(dg)
+	startCronScheduler(ctx, dg)
 
 	err = dg.Open()
 	if err != nil {
-		log.Fatal("Błąd otwierania połączenia:", err)
+		fatal("błąd otwierania połączenia", "error", err)
 	}
-	defer dg.Close()
 
-	fmt.Println("Bot działa! Codzienne cytaty o 9:00 CET. Naciśnij CTRL+C aby zakończyć.")
+	srv := startHealthServer(os.Getenv("HEALTH_ADDR"))
+
+	go jobScheduler.catchUpMissedRuns(time.Now())
+
+	slog.Info("bot działa, CTRL+C kończy")
 
-	sc := make(chan os.Signal, 1)
-	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	<-sc
+	sigCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	<-sigCtx.Done()
+	// kolejny sygnał zabija proces od razu
+	stopSignals()
+
+	shutdown(cancel, dg, srv, gate, shutdownTimeout())
 }
 
-func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+// runGemCLI generuje wykres GEM do pliku bez łączenia z Discordem:
+// ./main gem [plik.png] [pl|en]
+func runGemCLI(args []string) {
+	outputPath, lang := "gem.png", defaultLanguage
+	if len(args) > 0 {
+		outputPath = args[0]
+	}
+	if len(args) > 1 {
+		lang = args[1]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gemGenerateTimeout)
+	defer cancel()
+	summary, err := generateGemChart(ctx, outputPath, lang)
+	if err != nil {
+		fatal("nie udało się wygenerować wykresu", "error", err)
+	}
+	for _, r := range summary.Returns {
+		fmt.Printf("%s\t%+0.2f%%\n", r.Ticker, r.Return)
+	}
+	slog.Info("wykres zapisany", "path", outputPath)
+}
+
+func messageCreate(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate) {
 	if m.Author.ID == s.State.User.ID {
 		return
 	}
 
 	content := strings.TrimSpace(m.Content)
+	lang := langFor(m.GuildID)
+	if cmd, ok := commandName(content); ok {
+		recordCommand(cmd)
+		msgLogger(m).Debug("komenda", "command", cmd)
+	}
 
 	if content == "!zlotamysl" || content == "!zm" {
-		sendRandomQuote(s, m.ChannelID)
+		sendRandomQuote(s, m.ChannelID, lang)
 	} else if strings.HasPrefix(content, "!dodaj ") {
 		quote := strings.TrimPrefix(content, "!dodaj ")
-		config.Quotes = append(config.Quotes, quote)
-		saveConfig()
-		s.ChannelMessageSend(m.ChannelID, "✅ Dodano nową złotą myśl!")
+		updateConfig(func(c *Config) { c.Quotes = append(c.Quotes, quote) })
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "quote.added"))
 	} else if strings.HasPrefix(content, "!usun ") {
 		numStr := strings.TrimPrefix(content, "!usun ")
 		var num int
 		fmt.Sscanf(numStr, "%d", &num)
-		if num > 0 && num <= len(config.Quotes) {
-			config.Quotes = append(config.Quotes[:num-1], config.Quotes[num:]...)
-			saveConfig()
-			s.ChannelMessageSend(m.ChannelID, "✅ Usunięto złotą myśl!")
+		removed := false
+		updateConfig(func(c *Config) {
+			if num > 0 && num <= len(c.Quotes) {
+				c.Quotes = append(c.Quotes[:num-1], c.Quotes[num:]...)
+				removed = true
+			}
+		})
+		if removed {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "quote.removed"))
 		} else {
-			s.ChannelMessageSend(m.ChannelID, "❌ Nieprawidłowy numer!")
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "quote.bad_number"))
 		}
 	} else if content == "!lista" {
-		sendPaginatedList(s, m.ChannelID)
+		sendPaginatedList(s, m.ChannelID, lang)
 	} else if strings.HasPrefix(content, "!kanal ") {
 		channelID := strings.TrimPrefix(content, "!kanal ")
-		config.ChannelID = channelID
-		saveConfig()
-		s.ChannelMessageSend(m.ChannelID, "✅ Ustawiono kanał dla codziennych myśli!")
+		updateConfig(func(c *Config) { c.ChannelID = channelID })
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "quote.channel_set"))
 	} else if content == "!pomoc" {
-		help := `**🌟 Złote Myśli Bot - Komendy:**
-
-!zlotamysl lub !zm - Wyświetl losową złotą myśl
-!dodaj <tekst> - Dodaj nową złotą myśl
-!usun <numer> - Usuń złotą myśl (podaj numer z listy)
-!lista - Pokaż wszystkie złote myśli
-!kanal <ID> - Ustaw kanał dla codziennych myśli o 9:00
-!gem - Wygeneruj wykres ETF jako PNG
-!gemsubscribe - Zapisz się na miesięczny wykres ETF (ostatni dzień miesiąca, 10:00)
-!pomoc - Pokaż tę pomoc`
-		s.ChannelMessageSend(m.ChannelID, help)
-	} else if content == "!gem" {
-		statusMsg, statusErr := s.ChannelMessageSend(m.ChannelID, "⏳ Generuję wykres...")
-		if err := generateAndSendGem(s, m.ChannelID); err != nil {
-			log.Println("!gem error:", err)
-			if statusErr == nil && statusMsg != nil {
-				s.ChannelMessageDelete(m.ChannelID, statusMsg.ID)
-			}
-			s.ChannelMessageSend(m.ChannelID, "❌ Nie udało się wygenerować wykresu")
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "help"))
+	} else if content == "!gem" || strings.HasPrefix(content, "!gem ") {
+		opts, err := parseChartOptions(strings.TrimPrefix(content, "!gem"))
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, localizeError(lang, err)+"\n"+tr(lang, "gem.usage"))
 			return
 		}
-		if statusErr == nil && statusMsg != nil {
-			s.ChannelMessageDelete(m.ChannelID, statusMsg.ID)
+		if opts.Theme == "" {
+			opts.Theme = chartThemeFor(m.GuildID)
 		}
+		sendChartWithStatus(ctx, s, m, lang, "!gem", func(ctx context.Context) error {
+			return generateAndSendGem(ctx, s, m.ChannelID, "", lang, opts)
+		})
+	} else if content == "!wykres" || strings.HasPrefix(content, "!wykres ") {
+		spec, opts, err := parseComparison(strings.TrimPrefix(content, "!wykres"))
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, localizeError(lang, err)+"\n"+tr(lang, "compare.usage"))
+			return
+		}
+		if opts.Theme == "" {
+			opts.Theme = chartThemeFor(m.GuildID)
+		}
+		sendChartWithStatus(ctx, s, m, lang, "!wykres", func(ctx context.Context) error {
+			return generateAndSendComparison(ctx, s, m.ChannelID, lang, spec, opts)
+		})
 	} else if content == "!gemsubscribe" {
-		added := addGemSubscriber(m.Author.ID)
-		config.GemChannelID = m.ChannelID
-		saveConfig()
+		var added bool
+		updateConfig(func(c *Config) {
+			added = addGemSubscriber(c, m.Author.ID)
+			c.GemChannelID = m.ChannelID
+		})
 		if added {
-			s.ChannelMessageSend(m.ChannelID, "✅ Zapisano na miesięczny wykres ETF. Ostatni dzień miesiąca o 10:00 wrzucę wykres i oznaczę zapisanych.")
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "gem.subscribed"))
 		} else {
-			s.ChannelMessageSend(m.ChannelID, "✅ Już jesteś zapisany. Ostatni dzień miesiąca o 10:00 wrzucę wykres i oznaczę zapisanych.")
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "gem.already_subscribed"))
 		}
-	} else if content == "!pogoda" {
-		msg := buildTomorrowWeatherMessage()
-		if msg == "" {
-			s.ChannelMessageSend(m.ChannelID, "❌ Nie udało się pobrać prognozy")
-			return
+	} else if strings.HasPrefix(content, "!alert ") {
+		handlePriceAlertCommand(ctx, s, m, strings.TrimPrefix(content, "!alert "))
+	} else if content == "!alerty" || strings.HasPrefix(content, "!alerty ") {
+		handlePriceAlertsCommand(s, m, strings.TrimPrefix(content, "!alerty"))
+	} else if content == "!pogoda" || strings.HasPrefix(content, "!pogoda ") {
+		handleWeatherCommand(ctx, s, m, strings.TrimPrefix(content, "!pogoda"))
+	} else if content == "!harmonogram" || strings.HasPrefix(content, "!harmonogram ") {
+		handleScheduleCommand(s, m, strings.TrimPrefix(content, "!harmonogram"))
+	} else if strings.HasPrefix(content, "!przypomnij ") {
+		handleRemindCommand(s, m, strings.TrimPrefix(content, "!przypomnij "))
+	} else if content == "!przypomnienia" || strings.HasPrefix(content, "!przypomnienia ") {
+		handleRemindersCommand(s, m, strings.TrimPrefix(content, "!przypomnienia"))
+	} else if strings.HasPrefix(content, "!ustawienia") {
+		handleSettingsCommand(s, m, strings.TrimPrefix(content, "!ustawienia"))
+	} else if strings.HasPrefix(content, "!awarie") {
+		handleFailureAlertsCommand(s, m, strings.TrimPrefix(content, "!awarie"))
+	} else if content == "!jezyk" || strings.HasPrefix(content, "!jezyk ") {
+		handleLanguageCommand(s, m, strings.TrimPrefix(content, "!jezyk"))
+	} else if content == "!embedy on" || content == "!embedy off" {
+		enabled := content == "!embedy on"
+		updateConfig(func(c *Config) { setEmbedsEnabled(c, m.ChannelID, enabled) })
+		if enabled {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "embeds.on"))
+		} else {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "embeds.off"))
 		}
-		s.ChannelMessageSend(m.ChannelID, msg)
 	}
 }
 
-func addGemSubscriber(userID string) bool {
-	for _, id := range config.GemSubscribers {
+// botCommands to komendy zliczane w metrykach; inne słowa z "!" nie trafiają do etykiet.
+var botCommands = map[string]bool{
+	"!zlotamysl": true, "!zm": true, "!dodaj": true, "!usun": true, "!lista": true,
+	"!kanal": true, "!pomoc": true, "!gem": true, "!wykres": true, "!gemsubscribe": true, "!alert": true, "!alerty": true, "!pogoda": true,
+	"!harmonogram": true, "!przypomnij": true, "!przypomnienia": true, "!ustawienia": true,
+	"!awarie": true, "!jezyk": true, "!embedy": true,
+}
+
+func commandName(content string) (string, bool) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 || !botCommands[fields[0]] {
+		return "", false
+	}
+	return fields[0], true
+}
+
+func addGemSubscriber(c *Config, userID string) bool {
+	for _, id := range c.GemSubscribers {
 		if id == userID {
 			return false
 		}
 	}
-	config.GemSubscribers = append(config.GemSubscribers, userID)
+	c.GemSubscribers = append(c.GemSubscribers, userID)
 	return true
 }
 
@@ -147,11 +232,14 @@ func isLastDayOfMonth(t time.Time) bool {
 }
 
 func mentionGemSubscribers() string {
-	if len(config.GemSubscribers) == 0 {
+	configMu.Lock()
+	subscribers := slices.Clone(config.GemSubscribers)
+	configMu.Unlock()
+	if len(subscribers) == 0 {
 		return ""
 	}
 	var b strings.Builder
-	for i, id := range config.GemSubscribers {
+	for i, id := range subscribers {
 		if i > 0 {
 			b.WriteString(" ")
 		}
@@ -162,128 +250,97 @@ func mentionGemSubscribers() string {
 	return b.String()
 }
 
-func generateAndSendGem(s *discordgo.Session, channelID string) error {
-	tmpDir := os.TempDir()
-	outputPath := filepath.Join(tmpDir, fmt.Sprintf("gem_%d.png", time.Now().UnixNano()))
-
-	if err := generateGemChart(outputPath); err != nil {
-		return err
-	}
-
-	defer os.Remove(outputPath)
-
-	file, err := os.Open(outputPath)
+func generateAndSendGem(ctx context.Context, s *discordgo.Session, channelID, content, lang string, opts chartOptions) error {
+	var buf bytes.Buffer
+	summary, err := renderGemChart(ctx, &buf, lang, opts)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	_, err = s.ChannelFileSend(channelID, "etfs_rok.png", file)
-	return err
-}
 
-func sendRandomQuote(s *discordgo.Session, channelID string) {
-	if len(config.Quotes) == 0 {
-		s.ChannelMessageSend(channelID, "Brak złotych myśli! Dodaj je komendą !dodaj")
-		return
-	}
-	quote := config.Quotes[rand.Intn(len(config.Quotes))]
-	s.ChannelMessageSend(channelID, fmt.Sprintf("✨ **Złota Myśl:** ✨\n\n*%s*", quote))
+	msg := gemMessage(lang, summary, &discordgo.File{
+		Name:        opts.fileName("etfs_rok"),
+		ContentType: opts.contentType(),
+		Reader:      bytes.NewReader(buf.Bytes()),
+	})
+	msg.Content = content
+	return sendRich(s, channelID, msg)
 }
 
-func startCronScheduler(s *discordgo.Session) {
-	loc, err := time.LoadLocation("Europe/Warsaw")
+func generateAndSendComparison(ctx context.Context, s *discordgo.Session, channelID, lang string, spec comparisonSpec, opts chartOptions) error {
+	var buf bytes.Buffer
+	summary, err := renderComparisonChart(ctx, &buf, lang, spec, opts)
 	if err != nil {
-		log.Fatal("Location error:", err)
+		return err
 	}
+	return sendRich(s, channelID, comparisonMessage(lang, summary, &discordgo.File{
+		Name:        opts.fileName("wykres"),
+		ContentType: opts.contentType(),
+		Reader:      bytes.NewReader(buf.Bytes()),
+	}))
+}
 
-	c := cron.New(cron.WithLocation(loc))
-
-	_, err = c.AddFunc("0 9 * * ?", func() {
-		fmt.Println("🕐 CRON 9:00 CET!")
-		if config.ChannelID != "" {
-			// ZMIENIONO: "Złota myśl dnia" zamiast zwykłej złotej myśli
-			sendDailyQuote(s, config.ChannelID)
-		}
-	})
-	if err != nil {
-		log.Fatal("Cron AddFunc błąd:", err)
+// sendChartWithStatus pokazuje komunikat o generowaniu, wysyła wykres z limitem czasu
+// i usuwa komunikat; przy błędzie opisuje przyczynę na kanale.
+func sendChartWithStatus(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, lang, command string, send func(context.Context) error) {
+	statusMsg, statusErr := s.ChannelMessageSend(m.ChannelID, tr(lang, "gem.generating"))
+	chartCtx, cancel := context.WithTimeout(ctx, gemGenerateTimeout)
+	defer cancel()
+	err := send(chartCtx)
+	if statusErr == nil && statusMsg != nil {
+		s.ChannelMessageDelete(m.ChannelID, statusMsg.ID)
 	}
-
-	_, err = c.AddFunc("0 10 * * *", func() {
-		now := time.Now().In(loc)
-		if !isLastDayOfMonth(now) {
-			return
-		}
-		if config.GemChannelID == "" || len(config.GemSubscribers) == 0 {
-			return
-		}
-		if msg := mentionGemSubscribers(); msg != "" {
-			s.ChannelMessageSend(config.GemChannelID, msg)
-		}
-		if err := generateAndSendGem(s, config.GemChannelID); err != nil {
-			log.Println("scheduled gem error:", err)
-			s.ChannelMessageSend(config.GemChannelID, "❌ Nie udało się wygenerować wykresu")
-		}
-	})
 	if err != nil {
-		log.Fatal("Cron AddFunc błąd:", err)
+		msgLogger(m).Error("nie udało się wygenerować wykresu", "command", command, "error", err)
+		recordError(strings.TrimPrefix(command, "!"))
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "gem.failed", describeChartError(lang, err)))
 	}
+}
 
-	_, err = c.AddFunc("0 19 * * *", func() {
-		if config.GemChannelID == "" || len(config.GemSubscribers) == 0 {
-			return
-		}
-		msg := buildTomorrowWeatherMessage()
-		if msg == "" {
-			return
-		}
-		mention := mentionGemSubscribers()
-		if mention != "" {
-			msg = mention + "\n" + msg
-		}
-		s.ChannelMessageSend(config.GemChannelID, msg)
-	})
-	if err != nil {
-		log.Fatal("Cron AddFunc błąd:", err)
+func sendRandomQuote(s *discordgo.Session, channelID, lang string) {
+	list := quotes()
+	if len(list) == 0 {
+		s.ChannelMessageSend(channelID, tr(lang, "quote.empty"))
+		return
 	}
-
-	fmt.Println("✅ Cron działa - 9:00 CET codziennie!")
-	c.Start()
+	quote := list[rand.Intn(len(list))]
+	sendRich(s, channelID, quoteMessage(lang, tr(lang, "quote.title"), quote))
 }
 
 // NOWA FUNKCJA dla zaplanowanej złotej myśli dnia
-func sendDailyQuote(s *discordgo.Session, channelID string) {
-	if len(config.Quotes) == 0 {
-		s.ChannelMessageSend(channelID, "Brak złotych myśli! Dodaj je komendą !dodaj")
-		return
+func sendDailyQuote(s *discordgo.Session, channelID string) error {
+	lang := channelLang(s, channelID)
+	list := quotes()
+	if len(list) == 0 {
+		_, err := s.ChannelMessageSend(channelID, tr(lang, "quote.empty"))
+		return err
 	}
-	quote := config.Quotes[rand.Intn(len(config.Quotes))]
-	s.ChannelMessageSend(channelID, fmt.Sprintf("🌅 **Złota myśl dnia** 🌅\n\n*%s*", quote))
+	quote := list[rand.Intn(len(list))]
+	return sendRich(s, channelID, quoteMessage(lang, tr(lang, "quote.daily_title"), quote))
 }
 
-func sendPaginatedList(s *discordgo.Session, channelID string) {
-	if len(config.Quotes) == 0 {
-		s.ChannelMessageSend(channelID, "Brak złotych myśli!")
+func sendPaginatedList(s *discordgo.Session, channelID, lang string) {
+	list := quotes()
+	if len(list) == 0 {
+		s.ChannelMessageSend(channelID, tr(lang, "quote.none"))
 		return
 	}
 
 	const maxChars = 1800
 	const maxQuotesPerPage = 12
 
-	for i := 0; i < len(config.Quotes); i += maxQuotesPerPage {
+	for i := 0; i < len(list); i += maxQuotesPerPage {
 		end := i + maxQuotesPerPage
-		if end > len(config.Quotes) {
-			end = len(config.Quotes)
+		if end > len(list) {
+			end = len(list)
 		}
 
 		var msg strings.Builder
-		msg.WriteString(fmt.Sprintf("**📜 Złote Myśli (%d-%d/%d):**\n\n", i+1, end, len(config.Quotes)))
+		msg.WriteString(tr(lang, "quote.list_header", i+1, end, len(list)))
 
 		pageChars := 50
 		for j := i; j < end; j++ {
 			quoteNum := fmt.Sprintf("%d. ", j+1)
-			quotePreview := config.Quotes[j]
+			quotePreview := list[j]
 
 			if len(quotePreview) > 100 {
 				quotePreview = quotePreview[:97] + "..."
@@ -300,108 +357,10 @@ func sendPaginatedList(s *discordgo.Session, channelID string) {
 
 		// POPRAWIONE: _ dla message, err dla błędu
 		if _, err := s.ChannelMessageSend(channelID, msg.String()); err != nil {
-			log.Println("Błąd wysyłania listy:", err)
+			slog.Error("błąd wysyłania listy", "channel", channelID, "error", err)
 			return
 		}
 
 		time.Sleep(1000 * time.Millisecond)
 	}
 }
-
-type weatherResponse struct {
-	Daily struct {
-		Time           []string  `json:"time"`
-		TemperatureMax []float64 `json:"temperature_2m_max"`
-		TemperatureMin []float64 `json:"temperature_2m_min"`
-		WeatherCode    []int     `json:"weathercode"`
-	} `json:"daily"`
-}
-
-type forecast struct {
-	MinC float64
-	MaxC float64
-	Code int
-	Date string
-}
-
-func buildTomorrowWeatherMessage() string {
-	lesna, err := fetchTomorrowForecast(51.0156, 15.2634)
-	if err != nil {
-		log.Println("weather Lesna error:", err)
-		return ""
-	}
-	bielsko, err := fetchTomorrowForecast(49.8224, 19.0469)
-	if err != nil {
-		log.Println("weather Bielsko error:", err)
-		return ""
-	}
-
-	var b strings.Builder
-	b.WriteString("🌤️ **Pogoda na jutro**\n")
-	b.WriteString(fmt.Sprintf("Leśna: %s, %.0f/%.0f°C\n", weatherDescription(lesna.Code), lesna.MinC, lesna.MaxC))
-	b.WriteString(fmt.Sprintf("Bielsko-Biała: %s, %.0f/%.0f°C", weatherDescription(bielsko.Code), bielsko.MinC, bielsko.MaxC))
-	return b.String()
-}
-
-func fetchTomorrowForecast(lat, lon float64) (forecast, error) {
-	url := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%.4f&longitude=%.4f&daily=temperature_2m_max,temperature_2m_min,weathercode&timezone=Europe/Warsaw&forecast_days=2", lat, lon)
-	resp, err := http.Get(url)
-	if err != nil {
-		return forecast{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return forecast{}, fmt.Errorf("bad status: %s", resp.Status)
-	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return forecast{}, err
-	}
-	var parsed weatherResponse
-	if err := json.Unmarshal(body, &parsed); err != nil {
-		return forecast{}, err
-	}
-	if len(parsed.Daily.Time) < 2 || len(parsed.Daily.TemperatureMax) < 2 || len(parsed.Daily.TemperatureMin) < 2 || len(parsed.Daily.WeatherCode) < 2 {
-		return forecast{}, fmt.Errorf("insufficient forecast data")
-	}
-
-	return forecast{
-		Date: parsed.Daily.Time[1],
-		MaxC: parsed.Daily.TemperatureMax[1],
-		MinC: parsed.Daily.TemperatureMin[1],
-		Code: parsed.Daily.WeatherCode[1],
-	}, nil
-}
-
-func weatherDescription(code int) string {
-	switch code {
-	case 0:
-		return "bezchmurnie"
-	case 1, 2, 3:
-		return "częściowe zachmurzenie"
-	case 45, 48:
-		return "mgła"
-	case 51, 53, 55:
-		return "mżawka"
-	case 56, 57:
-		return "marznąca mżawka"
-	case 61, 63, 65:
-		return "deszcz"
-	case 66, 67:
-		return "marznący deszcz"
-	case 71, 73, 75:
-		return "śnieg"
-	case 77:
-		return "ziarna śniegu"
-	case 80, 81, 82:
-		return "przelotne opady"
-	case 85, 86:
-		return "przelotne opady śniegu"
-	case 95:
-		return "burza"
-	case 96, 99:
-		return "burza z gradem"
-	default:
-		return "pogoda"
-	}
-}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// marketFetchTimeout ogranicza pojedyncze zapytanie do Yahoo.
+const marketFetchTimeout = 20 * time.Second
+
+var yahooClient = &http.Client{}
+
+// Interwały notowań obsługiwane przez Yahoo i wykresy.
+var marketIntervals = []string{"1d", "1wk", "1mo"}
+
+// marketSession to godziny sesji giełdy w jej strefie czasowej (minuty od północy).
+type marketSession struct {
+	Zone        string
+	Open, Close int
+}
+
+// marketSessions według sufiksu tickera z Yahoo; ticker bez sufiksu to giełda amerykańska.
+// Święta nie są tu uwzględniane; alerty cenowe pomijają dni, w których nie ma dzisiejszego notowania.
+var marketSessions = map[string]marketSession{
+	"":    {Zone: "America/New_York", Open: 9*60 + 30, Close: 16 * 60},
+	".L":  {Zone: "Europe/London", Open: 8 * 60, Close: 16*60 + 30},
+	".DE": {Zone: "Europe/Berlin", Open: 9 * 60, Close: 17*60 + 30},
+	".AS": {Zone: "Europe/Amsterdam", Open: 9 * 60, Close: 17*60 + 30},
+	".PA": {Zone: "Europe/Paris", Open: 9 * 60, Close: 17*60 + 30},
+	".MI": {Zone: "Europe/Rome", Open: 9 * 60, Close: 17*60 + 30},
+	".SW": {Zone: "Europe/Zurich", Open: 9 * 60, Close: 17*60 + 30},
+	".WA": {Zone: "Europe/Warsaw", Open: 9 * 60, Close: 17 * 60},
+}
+
+// marketSessionFor zwraca sesję giełdy tickera; false dla kursów walut (=X) i nieznanych giełd.
+func marketSessionFor(ticker string) (marketSession, bool) {
+	if strings.HasSuffix(ticker, "=X") {
+		return marketSession{}, false
+	}
+	suffix := ""
+	if i := strings.LastIndex(ticker, "."); i > 0 {
+		suffix = ticker[i:]
+	}
+	session, known := marketSessions[suffix]
+	return session, known
+}
+
+// marketLocation zwraca strefę czasową giełdy tickera, a dla nieznanych UTC.
+func marketLocation(ticker string) *time.Location {
+	if session, ok := marketSessionFor(ticker); ok {
+		if loc, err := time.LoadLocation(session.Zone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// isMarketOpen mówi, czy giełda tickera ma teraz sesję. Kursy walut (=X) i giełdy
+// spoza marketSessions traktujemy jako otwarte przez cały dzień roboczy.
+func isMarketOpen(ticker string, now time.Time) bool {
+	session, known := marketSessionFor(ticker)
+	if !known {
+		wd := now.Weekday()
+		return wd != time.Saturday && wd != time.Sunday
+	}
+	loc, err := time.LoadLocation(session.Zone)
+	if err != nil {
+		return true
+	}
+	local := now.In(loc)
+	if wd := local.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	minute := local.Hour()*60 + local.Minute()
+	return minute >= session.Open && minute < session.Close
+}
+
+// tradedToday mówi, czy ostatnie notowanie pochodzi z dzisiejszej sesji giełdy tickera.
+// W święta Yahoo zwraca jako ostatnie notowanie z poprzedniego dnia.
+func tradedToday(ticker string, last, now time.Time) bool {
+	loc := marketLocation(ticker)
+	y1, m1, d1 := last.In(loc).Date()
+	y2, m2, d2 := now.In(loc).Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// priceTable to notowania kilku tickerów wyrównane do wspólnych dat.
+// Brakujące sesje są uzupełniane ostatnią znaną ceną, a okres przed
+// pierwszym notowaniem danego tickera ma NaN.
+type priceTable struct {
+	Times   []time.Time
+	Tickers []string
+	Values  map[string][]float64
+	// Currencies to waluta notowań według tickera, z metadanych Yahoo (np. "USD", "GBp").
+	Currencies map[string]string
+}
+
+// firstComplete zwraca pierwszy indeks nie wcześniejszy niż from, w którym
+// wszystkie tickery mają cenę, albo len(Times), gdy takiego nie ma.
+func (t priceTable) firstComplete(from time.Time) int {
+	for i := range t.Times {
+		if t.Times[i].Before(from) {
+			continue
+		}
+		ok := true
+		for _, ticker := range t.Tickers {
+			if math.IsNaN(t.Values[ticker][i]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return i
+		}
+	}
+	return len(t.Times)
+}
+
+// fetchPriceTable pobiera równolegle notowania tickerów. Przy pierwszym błędzie
+// anuluje pozostałe zapytania, chyba że partial pozwala pominąć tickery bez danych;
+// wtedy zwraca je w missing. Notowania z różnych giełd są łączone po dacie w strefie loc.
+func fetchPriceTable(ctx context.Context, tickers []string, start, end time.Time, interval string, partial bool, loc *time.Location) (table priceTable, missing []string, err error) {
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// przy pierwszym błędzie funkcja wraca, zanim skończą się pozostałe zapytania
+	client := yahooClient
+
+	type fetchResult struct {
+		ticker string
+		series yahooSeries
+		err    error
+	}
+
+	results := make(chan fetchResult, len(tickers))
+	for _, ticker := range tickers {
+		go func(t string) {
+			reqCtx, cancelReq := context.WithTimeout(fetchCtx, marketFetchTimeout)
+			defer cancelReq()
+			started := time.Now()
+			series, fetchErr := fetchYahooSeries(reqCtx, client, t, start, end, interval)
+			observeAPI("yahoo", time.Since(started), fetchErr)
+			results <- fetchResult{ticker: t, series: series, err: fetchErr}
+		}(ticker)
+	}
+
+	day := func(ts int64) time.Time {
+		y, m, d := time.Unix(ts, 0).In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+
+	var firstErr error
+	seriesByTicker := make(map[string]map[time.Time]float64, len(tickers))
+	currencies := make(map[string]string, len(tickers))
+	days := make(map[time.Time]bool)
+	for range tickers {
+		res := <-results
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = &tickerFetchError{Ticker: res.ticker, Err: res.err}
+			}
+			if !partial {
+				return priceTable{}, nil, firstErr
+			}
+			slog.Warn("pomijam ticker bez danych", "ticker", res.ticker, "error", res.err)
+			missing = append(missing, res.ticker)
+			continue
+		}
+		points := make(map[time.Time]float64, len(res.series.Timestamps))
+		for idx, ts := range res.series.Timestamps {
+			d := day(ts)
+			points[d] = res.series.Closes[idx]
+			days[d] = true
+		}
+		seriesByTicker[res.ticker] = points
+		currencies[res.ticker] = res.series.Currency
+	}
+
+	if len(seriesByTicker) == 0 && firstErr != nil {
+		return priceTable{}, missing, firstErr
+	}
+	if len(days) == 0 {
+		return priceTable{}, missing, trError("chart.no_data")
+	}
+
+	for _, ticker := range tickers {
+		if _, ok := seriesByTicker[ticker]; ok {
+			table.Tickers = append(table.Tickers, ticker)
+		}
+	}
+	for d := range days {
+		table.Times = append(table.Times, d)
+	}
+	sort.Slice(table.Times, func(i, j int) bool { return table.Times[i].Before(table.Times[j]) })
+
+	table.Currencies = currencies
+	table.Values = make(map[string][]float64, len(table.Tickers))
+	for _, ticker := range table.Tickers {
+		values := make([]float64, len(table.Times))
+		last := math.NaN()
+		for i, d := range table.Times {
+			if v, ok := seriesByTicker[ticker][d]; ok && !math.IsNaN(v) {
+				last = v
+			}
+			values[i] = last
+		}
+		table.Values[ticker] = values
+	}
+	return table, missing, nil
+}
+
+// tickerFetchError mówi, dla którego tickera nie udało się pobrać danych.
+type tickerFetchError struct {
+	Ticker string
+	Err    error
+}
+
+func (e *tickerFetchError) Error() string {
+	return e.Ticker + ": " + e.Err.Error()
+}
+
+func (e *tickerFetchError) Unwrap() error {
+	return e.Err
+}
+
+// fetchRetryHint dobiera podpowiedź dla użytkownika do przyczyny błędu pobierania.
+func fetchRetryHint(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "gem.hint.timeout"
+	}
+	var le *localizedError
+	if errors.As(err, &le) {
+		switch le.Key {
+		case "gem.err.status":
+			switch code, _ := le.Args[0].(int); code {
+			case http.StatusTooManyRequests:
+				return "gem.hint.rate_limit"
+			case http.StatusNotFound:
+				return "gem.hint.ticker"
+			}
+		case "gem.err.no_results":
+			return "gem.hint.ticker"
+		}
+	}
+	return "gem.hint.retry"
+}
+
+// describeChartError tłumaczy błąd generowania wykresu; przy błędzie pobierania
+// podaje ticker i podpowiedź, kiedy spróbować ponownie.
+func describeChartError(lang string, err error) string {
+	var fe *tickerFetchError
+	if errors.As(err, &fe) {
+		return tr(lang, "gem.fetch_failed", fe.Ticker, localizeError(lang, fe.Err), tr(lang, fetchRetryHint(fe.Err)))
+	}
+	return localizeError(lang, err)
+}
+
+type yahooChartResponse struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				Currency string `json:"currency"`
+			} `json:"meta"`
+			Timestamp  []int64 `json:"timestamp"`
+			Indicators struct {
+				Quote []struct {
+					Close []*float64 `json:"close"`
+				} `json:"quote"`
+			} `json:"indicators"`
+		} `json:"result"`
+		Error interface{} `json:"error"`
+	} `json:"chart"`
+}
+
+// yahooSeries to ceny zamknięcia jednego tickera i waluta, w której są notowane.
+type yahooSeries struct {
+	Timestamps []int64
+	Closes     []float64
+	Currency   string
+}
+
+// fetchYahooSeries pobiera ceny zamknięcia z Yahoo w interwale 1d, 1wk albo 1mo.
+func fetchYahooSeries(ctx context.Context, client *http.Client, ticker string, start, end time.Time, interval string) (yahooSeries, error) {
+	requestURL := fmt.Sprintf(
+		"https://query2.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=%s&events=history&includeAdjustedClose=true",
+		url.PathEscape(ticker),
+		start.Unix(),
+		end.Unix(),
+		url.QueryEscape(interval),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return yahooSeries{}, err
+	}
+	req.Header.Set("User-Agent", "zlotemyslibot")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return yahooSeries{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return yahooSeries{}, trError("gem.err.status", resp.StatusCode, ticker)
+	}
+
+	var payload yahooChartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return yahooSeries{}, err
+	}
+
+	if len(payload.Chart.Result) == 0 {
+		return yahooSeries{}, trError("gem.err.no_results", ticker)
+	}
+
+	result := payload.Chart.Result[0]
+	if len(result.Timestamp) == 0 || len(result.Indicators.Quote) == 0 {
+		return yahooSeries{}, trError("gem.err.no_prices", ticker)
+	}
+
+	closings := result.Indicators.Quote[0].Close
+	if len(closings) != len(result.Timestamp) {
+		return yahooSeries{}, trError("gem.err.length", ticker)
+	}
+
+	values := make([]float64, len(result.Timestamp))
+	for i, v := range closings {
+		if v == nil || math.IsNaN(*v) {
+			values[i] = math.NaN()
+		} else {
+			values[i] = *v
+		}
+	}
+
+	return yahooSeries{Timestamps: result.Timestamp, Closes: values, Currency: result.Meta.Currency}, nil
+}
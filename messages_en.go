@@ -0,0 +1,287 @@
+package main
+
+var catalogEN = catalog{
+	messages: map[string]string{
+		"help": `**🌟 Golden Thoughts Bot - Commands:**
+
+!zlotamysl or !zm - Show a random golden thought
+!dodaj <text> - Add a new golden thought
+!usun <number> - Remove a golden thought (number from the list)
+!lista - Show all golden thoughts
+!kanal <ID> - Set the channel for the daily thought at 9:00
+!gem [png|svg|pdf] [light|dark] [momentum] [pionowy] [--dpi N] [--rozmiar WxH] [--waluta CODE] - Generate the ETF chart
+!wykres <tickers> [6m|2y] [1d|1wk|1mo] [procent|indeks] - Compare any tickers, e.g. !wykres SPY QQQ VWCE.DE 2y
+!alert <ticker> > <price> | < <price> | <change>% [Nd] [powtarzaj] - Price alert, e.g. !alert CNDX.L > 1200, !alert EIMI.L -5% 1d
+!alerty - Show your price alerts (!alerty usun <number> deletes one)
+!gemsubscribe - Subscribe to the monthly ETF chart (last day of the month, 10:00)
+!pogoda - Show tomorrow's weather forecast
+!pogoda <city> - Tomorrow's forecast for any place
+!pogoda 7d [city] - Weekly forecast with a chart
+!pogoda godzinowo [city] - Tomorrow's forecast hour by hour
+!pogoda dodaj <city> / !pogoda usun <city> / !pogoda lista - Manage locations
+!pogoda zapisz <city> - Daily forecast at 19:00 for your places
+!pogoda wypisz [city] / !pogoda moje / !pogoda dostawa dm|kanal - Manage your forecast
+!pogoda progi [city] [mroz|upal|opady|porywy|burza <value>] - Weather alert thresholds (frost, heat, rain, gusts, storm)
+!embedy on|off - Turn embeds on or off in this channel
+!harmonogram - Show scheduled jobs and their next run
+!harmonogram set <job> <cron> - Change a job's schedule (e.g. !harmonogram set pogoda 0 20 * * *)
+!ustawienia pogoda [serwer] [temp C|F] [wiatr kmh|ms] [opady mm|cale] - Weather units (server, temperature, wind, precipitation)
+!ustawienia wykres [light|dark] - Default chart theme for the server
+!przypomnij [dm] <when> <text> - Reminder, e.g. 2h, jutro 9:00, 2026-11-01 10:00, co piątek 16:00
+!przypomnienia - Show your reminders (!przypomnienia usun <number> cancels one)
+!awarie kanal|dm|off - Report failed jobs in this channel, by DM or not at all
+!jezyk pl|en - Bot language on this server
+!pomoc - Show this help`,
+
+		"language.current":    "Bot language on this server: **%s**. Change it with !jezyk pl|en",
+		"language.usage":      "❌ Usage: !jezyk %s",
+		"language.guild_only": "❌ The language can only be changed on a server!",
+		"language.set":        "✅ The bot will speak English on this server.",
+
+		"quote.added":       "✅ Added a new golden thought!",
+		"quote.removed":     "✅ Golden thought removed!",
+		"quote.bad_number":  "❌ Invalid number!",
+		"quote.channel_set": "✅ Daily thought channel set!",
+		"quote.empty":       "No golden thoughts yet! Add some with !dodaj",
+		"quote.none":        "No golden thoughts yet!",
+		"quote.title":       "✨ **Golden Thought:** ✨",
+		"quote.daily_title": "🌅 **Thought of the day** 🌅",
+		"quote.list_header": "**📜 Golden Thoughts (%d-%d/%d):**\n\n",
+
+		"embeds.on":  "✅ Embeds enabled in this channel.",
+		"embeds.off": "✅ Embeds disabled, I'll send plain text.",
+
+		"chart.no_data":            "no data for the chart",
+		"chart.incomplete_data":    "no complete data for the chart",
+		"chart.err.missing_value":  "missing value after %s",
+		"chart.err.dpi":            "DPI must be a number from %d to %d",
+		"chart.err.size":           "give the size in inches as WxH, each dimension from %d to %d",
+		"chart.err.unknown_option": "unknown option: %s",
+		"chart.err.currency":       "invalid currency code: %s (e.g. PLN, USD, EUR)",
+		"chart.err.no_currency":    "cannot convert %s to %s: missing currency or exchange rate",
+		"chart.err.too_large":      "the chart would be too large, lower the DPI or size",
+
+		"gem.generating":         "⏳ Generating the chart...",
+		"gem.failed":             "❌ Failed to generate the chart: %s",
+		"gem.subscribed":         "✅ Subscribed to the monthly ETF chart. On the last day of the month at 10:00 I'll post the chart and mention subscribers.",
+		"gem.already_subscribed": "✅ You're already subscribed. On the last day of the month at 10:00 I'll post the chart and mention subscribers.",
+		"gem.title":              "ETF comparison - 1 year",
+		"gem.x_label":            "Monthly interval",
+		"gem.drawdown_title":     "Drawdown from peak",
+		"gem.momentum_title":     "12-month momentum",
+		"gem.signal":             "GEM signal: **%s**",
+		"gem.footer":             "Yahoo Finance • monthly interval",
+		"gem.leader":             "Leader: **%s** (%+0.2f%%)",
+		"gem.usage":              "Usage: `!gem [png|svg|pdf] [light|dark] [momentum] [pionowy] [--dpi N] [--rozmiar WxH] [--waluta CODE]`, e.g. `!gem svg`, `!gem dark momentum`, `!gem --dpi 200`, `!gem pionowy` (portrait), `!gem --waluta USD`",
+		"gem.fetch_failed":       "couldn't fetch data for %s (%s). %s",
+		"gem.missing":            "⚠️ No data for: %s",
+		"gem.hint.timeout":       "Yahoo is responding too slowly, try again in a minute.",
+		"gem.hint.rate_limit":    "Yahoo is rate limiting requests, try again in a few minutes.",
+		"gem.hint.ticker":        "Check that the symbol is correct.",
+		"gem.hint.retry":         "Try again in a few minutes.",
+		"compare.usage":          "Usage: `!wykres TICKER... [range] [1d|1wk|1mo] [procent|indeks]` plus the `!gem` options, e.g. `!wykres SPY QQQ VWCE.DE 2y`, `!wykres CNDX.L 6m indeks`, `!wykres EIMI.L 10y 1mo svg`",
+		"compare.title":          "Comparison – %s",
+		"compare.footer":         "Yahoo Finance • %s",
+		"compare.interval.1d":    "Daily interval",
+		"compare.interval.1wk":   "Weekly interval",
+		"compare.interval.1mo":   "Monthly interval",
+		"compare.err.no_tickers": "give at least one ticker",
+		"compare.err.too_many":   "at most %d tickers on one chart",
+		"compare.err.ticker":     "invalid ticker: %s",
+		"compare.err.range":      "give the range in months or years, e.g. 6m or 2y (at most %d years)",
+		"gem.err.zero_base":      "base value for %s is zero",
+		"gem.err.bad_return":     "invalid return data for %s",
+		"gem.err.status":         "yahoo status %d for %s",
+		"gem.err.no_results":     "no results for %s",
+		"gem.err.no_prices":      "no price data for %s",
+		"gem.err.length":         "mismatched data length for %s",
+
+		"weather.tomorrow_title":   "🌤️ Tomorrow's weather",
+		"weather.week_title":       "📅 7-day weather",
+		"weather.week_chart_title": "7-day forecast (solid: max, dashed: min)",
+		"weather.hourly_title":     "🕐 %s: tomorrow hour by hour",
+		"weather.hourly_header":    "hour  temp  rain   wind",
+		"weather.footer":           "Open-Meteo • forecast for %s",
+		"weather.line":             "%s %s: %s, %.0f/%.0f%s, precipitation %.1f %s (%.0f%%), wind %.0f %s (gusts %.0f), UV %.0f, 🌅 %s 🌇 %s",
+		"weather.details":          "%s %s\n🌡️ %.0f / %.0f%s\n💧 %.1f %s (%.0f%%)\n💨 %.0f %s, gusts %.0f %s\n🔆 UV %.0f\n🌅 %s  🌇 %s",
+		"weather.fetch_failed":     "❌ Failed to fetch the forecast",
+		"weather.not_found":        "❌ Couldn't find the place %q",
+		"weather.location_unknown": "❌ Couldn't find the place",
+		"weather.location_exists":  "✅ %s is already on the list.",
+		"weather.location_added":   "✅ Added %s (%s, %.4f, %.4f)",
+		"weather.location_missing": "❌ %q is not on the list!",
+		"weather.location_removed": "✅ Removed %s from the list.",
+		"weather.location_usage":   "❌ Usage: !pogoda dodaj <city> or !pogoda usun <city>",
+		"weather.no_locations":     "No locations! Add one with !pogoda dodaj <city>",
+		"weather.locations_header": "**📍 Weather locations:**\n",
+
+		"weather.code.unknown": "weather",
+		"weather.code.0":       "clear sky",
+		"weather.code.1":       "mainly clear",
+		"weather.code.2":       "partly cloudy",
+		"weather.code.3":       "overcast",
+		"weather.code.45":      "fog",
+		"weather.code.48":      "depositing rime fog",
+		"weather.code.51":      "light drizzle",
+		"weather.code.53":      "moderate drizzle",
+		"weather.code.55":      "dense drizzle",
+		"weather.code.56":      "light freezing drizzle",
+		"weather.code.57":      "dense freezing drizzle",
+		"weather.code.61":      "slight rain",
+		"weather.code.63":      "moderate rain",
+		"weather.code.65":      "heavy rain",
+		"weather.code.66":      "light freezing rain",
+		"weather.code.67":      "heavy freezing rain",
+		"weather.code.71":      "slight snow",
+		"weather.code.73":      "moderate snow",
+		"weather.code.75":      "heavy snow",
+		"weather.code.77":      "snow grains",
+		"weather.code.80":      "slight rain showers",
+		"weather.code.81":      "moderate rain showers",
+		"weather.code.82":      "violent rain showers",
+		"weather.code.85":      "slight snow showers",
+		"weather.code.86":      "heavy snow showers",
+		"weather.code.95":      "thunderstorm",
+		"weather.code.96":      "thunderstorm with slight hail",
+		"weather.code.99":      "thunderstorm with heavy hail",
+
+		"weekday.0":       "Sunday",
+		"weekday.1":       "Monday",
+		"weekday.2":       "Tuesday",
+		"weekday.3":       "Wednesday",
+		"weekday.4":       "Thursday",
+		"weekday.5":       "Friday",
+		"weekday.6":       "Saturday",
+		"weekday.short.0": "Sun",
+		"weekday.short.1": "Mon",
+		"weekday.short.2": "Tue",
+		"weekday.short.3": "Wed",
+		"weekday.short.4": "Thu",
+		"weekday.short.5": "Fri",
+		"weekday.short.6": "Sat",
+
+		"subscription.already":          "✅ %s is already in your forecast.",
+		"subscription.added":            "✅ Added %s to your daily forecast (%s).",
+		"subscription.none":             "❌ You have no weather subscription!",
+		"subscription.none_hint":        "You have no weather subscription! Subscribe with !pogoda zapisz <city>",
+		"subscription.cancelled":        "✅ Unsubscribed from the daily forecast.",
+		"subscription.missing":          "❌ %q is not in your forecast!",
+		"subscription.removed":          "✅ Removed %s from your forecast.",
+		"subscription.subscribe_first":  "❌ Subscribe first: !pogoda zapisz <city>",
+		"subscription.delivery_usage":   "❌ Usage: !pogoda dostawa dm|kanal",
+		"subscription.delivery_set":     "✅ You'll get the forecast %s.",
+		"subscription.delivery_dm":      "by direct message",
+		"subscription.delivery_channel": "in <#%s>",
+		"subscription.info":             "**🌤️ Your forecast:** %s\nDelivery: %s",
+
+		"alert.title":        "⚠️ **Weather warning — %s**",
+		"alert.mroz":         "🥶 frost down to %.0f°C",
+		"alert.upal":         "🥵 heat up to %.0f°C",
+		"alert.opady":        "🌧️ heavy precipitation %.0f mm",
+		"alert.porywy":       "💨 wind gusts up to %.0f km/h",
+		"alert.fetch_failed": "failed to fetch the forecast: %s",
+
+		"thresholds.default":   "Default thresholds: %s\nChange: !pogoda progi <city> mroz|upal|opady|porywy|burza <value>",
+		"thresholds.for":       "Thresholds for %s: %s",
+		"thresholds.saved":     "✅ Thresholds for %s: %s",
+		"thresholds.unknown":   "❌ Unknown threshold! Available: mroz (frost), upal (heat), opady (precipitation), porywy (gusts), burza (storm)",
+		"thresholds.bad_value": "❌ Invalid value!",
+		"thresholds.describe":  "frost ≤ %.0f°C, heat ≥ %.0f°C, precipitation ≥ %.0f mm, gusts ≥ %.0f km/h, storms %s",
+		"thresholds.on":        "on",
+		"thresholds.off":       "off",
+
+		"units.describe":    "temperature %s, wind %s, precipitation %s",
+		"units.usage":       "❌ Usage: !ustawienia pogoda [serwer] [temp C|F] [wiatr kmh|ms] [opady mm|cale] or !ustawienia wykres [light|dark]",
+		"theme.current":     "🎨 Server chart theme: **%s**",
+		"theme.usage":       "❌ Usage: !ustawienia wykres [%s]",
+		"theme.set":         "✅ Server chart theme: **%s**",
+		"units.guild_only":  "❌ Server settings can only be changed on a server!",
+		"units.current":     "**⚙️ Weather units**\nYours: %s\nServer: %s",
+		"units.pairs":       "❌ Give pairs: <setting> <value>, e.g. !ustawienia pogoda temp F wiatr ms",
+		"units.guild_saved": "✅ Server units: %s",
+		"units.user_saved":  "✅ Your units: %s",
+		"units.err.temp":    "temperature: C or F",
+		"units.err.wind":    "wind: kmh or ms",
+		"units.err.precip":  "precipitation: mm or cale (inches)",
+		"units.err.unknown": "unknown setting %q",
+
+		"job.cytat":            "Thought of the day",
+		"job.gem":              "Monthly ETF chart (last day of the month)",
+		"job.pogoda":           "Tomorrow's weather forecast",
+		"job.ostrzezenia":      "Weather warnings for subscribed locations",
+		"job.alerty":           "Price alerts (during exchange hours)",
+		"job.failed_title":     "🚨 **Job %s failed**\n",
+		"job.failed_scheduled": "Scheduled: %s\n",
+		"job.attempt_ok":       "ok",
+
+		"failures.channel": "✅ Job failures will be reported in this channel.",
+		"failures.dm":      "✅ I'll report job failures to you by direct message.",
+		"failures.off":     "✅ Job failure reports disabled.",
+		"failures.usage":   "❌ Usage: !awarie kanal|dm|off",
+
+		"schedule.header":          "**🗓️ Job schedule:**\n",
+		"schedule.entry":           "\n**%s** — %s\n`%s` (%s), next: %s\n",
+		"schedule.disabled":        "disabled",
+		"schedule.usage":           "❌ Usage: !harmonogram set <job> <cron>",
+		"schedule.updated":         "✅ Job **%s** has a new schedule: `%s`",
+		"schedule.next_run":        "\nNext run: %s",
+		"schedule.err.timezone":    "unknown time zone %q",
+		"schedule.err.spec":        "invalid schedule %q: %v",
+		"schedule.err.catch_up":    "invalid catch-up window %q",
+		"schedule.err.backoff":     "invalid retry backoff %q",
+		"schedule.err.retries":     "retry count cannot be negative",
+		"schedule.err.unknown_job": "no such job %q",
+
+		"pricealert.usage":               "Usage: `!alert TICKER > PRICE`, `!alert TICKER < PRICE` or `!alert TICKER -5% 1d` (change within N days); add `powtarzaj` to re-arm the alert after each trigger",
+		"pricealert.added":               "✅ Alert #%d: %s (%s). Now: %.2f %s",
+		"pricealert.fired":               "🔔 <@%s> alert #%d: %s — now %.2f %s",
+		"pricealert.fired_change":        "🔔 <@%s> alert #%d: %s — change %+.2f%%, now %.2f %s",
+		"pricealert.disarmed":            "Alert disarmed, delete it with !alerty usun %d",
+		"pricealert.rearm_hint":          "The alert will trigger again once the condition clears and is met again",
+		"pricealert.rule_change":         "%s %+g%% within %s",
+		"pricealert.status.armed":        "armed",
+		"pricealert.status.armed_repeat": "armed, repeating",
+		"pricealert.status.waiting":      "triggered %s, waiting for the condition to clear",
+		"pricealert.status.disarmed":     "disarmed, triggered %s",
+		"pricealert.none":                "You have no price alerts! Add one with !alert",
+		"pricealert.not_found":           "❌ You have no alert with that number!",
+		"pricealert.deleted":             "✅ Deleted alert #%d",
+		"pricealert.delete_hint":         "Delete: !alerty usun <number>",
+		"pricealert.too_many":            "❌ You can have at most %d price alerts",
+		"pricealert.ticker_failed":       "❌ Could not check the ticker: %s",
+		"pricealert.fetch_failed":        "could not fetch prices for: %s",
+		"pricealert.err.incomplete":      "give a ticker and a condition",
+		"pricealert.err.price":           "invalid price %q",
+		"pricealert.err.change":          "invalid change %q, e.g. -5%%",
+		"pricealert.err.window":          "give the window in days, e.g. 1d or 5d (at most %d)",
+		"pricealert.err.unknown":         "I don't understand %q",
+
+		"reminder.fired":           "⏰ <@%s> reminder: %s",
+		"reminder.added":           "✅ Reminder #%d: %s — %s",
+		"reminder.examples":        "Examples (times are written in Polish): !przypomnij 2h buy ETF, !przypomnij 2026-11-01 10:00 rebalance, !przypomnij co piątek 16:00 report",
+		"reminder.schedule_failed": "❌ Failed to schedule the reminder",
+		"reminder.not_found":       "❌ You have no reminder with that number!",
+		"reminder.cancelled":       "✅ Cancelled reminder #%d",
+		"reminder.none":            "You have no reminders! Add one with !przypomnij",
+		"reminder.cancel_hint":     "Cancel with: !przypomnienia usun <number>",
+		"reminder.daily":           "daily %02d:%02d",
+		"reminder.weekly":          "every %s %02d:%02d",
+		"reminder.err.no_time":     "missing reminder time",
+		"reminder.err.no_text":     "missing reminder text",
+		"reminder.err.no_day":      "give a day, e.g. co piątek 16:00",
+		"reminder.err.unknown":     "I don't understand %q",
+		"reminder.err.bad_time":    "I don't understand the time %q",
+		"reminder.err.past":        "that time has already passed",
+		"reminder.err.bad_date":    "there is no such date: %s",
+		"reminder.err.too_far":     "reminders can be set at most %d years ahead",
+	},
+	plurals: map[string][]string{
+		"quote.footer":           {"%d golden thought in the collection", "%d golden thoughts in the collection"},
+		"job.failed_attempts":    {"%d attempt\n", "%d attempts\n"},
+		"compare.range.months":   {"%d month", "%d months"},
+		"compare.range.years":    {"%d year", "%d years"},
+		"pricealert.list_header": {"**🔔 You have %d price alert:**\n", "**🔔 You have %d price alerts:**\n"},
+		"pricealert.days":        {"%d day", "%d days"},
+		"reminder.list_header":   {"**⏰ You have %d reminder:**\n", "**⏰ You have %d reminders:**\n"},
+	},
+}
@@ -0,0 +1,287 @@
+package main
+
+var catalogPL = catalog{
+	messages: map[string]string{
+		"help": `**🌟 Złote Myśli Bot - Komendy:**
+
+!zlotamysl lub !zm - Wyświetl losową złotą myśl
+!dodaj <tekst> - Dodaj nową złotą myśl
+!usun <numer> - Usuń złotą myśl (podaj numer z listy)
+!lista - Pokaż wszystkie złote myśli
+!kanal <ID> - Ustaw kanał dla codziennych myśli o 9:00
+!gem [png|svg|pdf] [light|dark] [momentum] [pionowy] [--dpi N] [--rozmiar SZERxWYS] [--waluta KOD] - Wygeneruj wykres ETF
+!wykres <tickery> [6m|2y] [1d|1wk|1mo] [procent|indeks] - Porównaj dowolne tickery, np. !wykres SPY QQQ VWCE.DE 2y
+!alert <ticker> > <cena> | < <cena> | <zmiana>% [Nd] [powtarzaj] - Alert cenowy, np. !alert CNDX.L > 1200, !alert EIMI.L -5% 1d
+!alerty - Pokaż swoje alerty cenowe (!alerty usun <numer> usuwa)
+!gemsubscribe - Zapisz się na miesięczny wykres ETF (ostatni dzień miesiąca, 10:00)
+!pogoda - Pokaż prognozę pogody na jutro
+!pogoda <miasto> - Prognoza na jutro dla dowolnej miejscowości
+!pogoda 7d [miasto] - Prognoza na tydzień z wykresem
+!pogoda godzinowo [miasto] - Jutrzejsza prognoza godzina po godzinie
+!pogoda dodaj <miasto> / !pogoda usun <miasto> / !pogoda lista - Zarządzaj lokalizacjami
+!pogoda zapisz <miasto> - Codzienna prognoza o 19:00 dla Twoich miejscowości
+!pogoda wypisz [miasto] / !pogoda moje / !pogoda dostawa dm|kanal - Zarządzaj swoją prognozą
+!pogoda progi [miasto] [mroz|upal|opady|porywy|burza <wartość>] - Progi ostrzeżeń pogodowych
+!embedy on|off - Włącz lub wyłącz embedy na tym kanale
+!harmonogram - Pokaż zaplanowane zadania i ich najbliższe uruchomienie
+!harmonogram set <zadanie> <cron> - Zmień harmonogram zadania (np. !harmonogram set pogoda 0 20 * * *)
+!ustawienia pogoda [serwer] [temp C|F] [wiatr kmh|ms] [opady mm|cale] - Jednostki pogody
+!ustawienia wykres [light|dark] - Domyślny motyw wykresów serwera
+!przypomnij [dm] <kiedy> <tekst> - Przypomnienie, np. 2h, jutro 9:00, 2026-11-01 10:00, co piątek 16:00
+!przypomnienia - Pokaż swoje przypomnienia (!przypomnienia usun <numer> anuluje)
+!awarie kanal|dm|off - Zgłaszaj nieudane zadania na tym kanale, w DM albo wcale
+!jezyk pl|en - Język bota na tym serwerze
+!pomoc - Pokaż tę pomoc`,
+
+		"language.current":    "Język bota na tym serwerze: **%s**. Zmiana: !jezyk pl|en",
+		"language.usage":      "❌ Użycie: !jezyk %s",
+		"language.guild_only": "❌ Język można zmienić tylko na serwerze!",
+		"language.set":        "✅ Bot będzie mówić po polsku na tym serwerze.",
+
+		"quote.added":       "✅ Dodano nową złotą myśl!",
+		"quote.removed":     "✅ Usunięto złotą myśl!",
+		"quote.bad_number":  "❌ Nieprawidłowy numer!",
+		"quote.channel_set": "✅ Ustawiono kanał dla codziennych myśli!",
+		"quote.empty":       "Brak złotych myśli! Dodaj je komendą !dodaj",
+		"quote.none":        "Brak złotych myśli!",
+		"quote.title":       "✨ **Złota Myśl:** ✨",
+		"quote.daily_title": "🌅 **Złota myśl dnia** 🌅",
+		"quote.list_header": "**📜 Złote Myśli (%d-%d/%d):**\n\n",
+
+		"embeds.on":  "✅ Embedy włączone na tym kanale.",
+		"embeds.off": "✅ Embedy wyłączone, będę wysyłać zwykły tekst.",
+
+		"chart.no_data":            "brak danych do wykresu",
+		"chart.incomplete_data":    "brak kompletnych danych do wykresu",
+		"chart.err.missing_value":  "brak wartości po %s",
+		"chart.err.dpi":            "DPI musi być liczbą od %d do %d",
+		"chart.err.size":           "rozmiar podaj w calach jako SZERxWYS, każdy wymiar od %d do %d",
+		"chart.err.unknown_option": "nieznana opcja: %s",
+		"chart.err.currency":       "niepoprawny kod waluty: %s (np. PLN, USD, EUR)",
+		"chart.err.no_currency":    "nie da się przeliczyć %s na %s: brak waluty albo kursu",
+		"chart.err.too_large":      "wykres byłby za duży, zmniejsz DPI albo rozmiar",
+
+		"gem.generating":         "⏳ Generuję wykres...",
+		"gem.failed":             "❌ Nie udało się wygenerować wykresu: %s",
+		"gem.subscribed":         "✅ Zapisano na miesięczny wykres ETF. Ostatni dzień miesiąca o 10:00 wrzucę wykres i oznaczę zapisanych.",
+		"gem.already_subscribed": "✅ Już jesteś zapisany. Ostatni dzień miesiąca o 10:00 wrzucę wykres i oznaczę zapisanych.",
+		"gem.title":              "Porównanie ETF - 1 rok",
+		"gem.x_label":            "Interwał Miesięczny",
+		"gem.drawdown_title":     "Spadek od szczytu",
+		"gem.momentum_title":     "Momentum 12 mies.",
+		"gem.signal":             "Sygnał GEM: **%s**",
+		"gem.footer":             "Yahoo Finance • interwał miesięczny",
+		"gem.leader":             "Lider: **%s** (%+0.2f%%)",
+		"gem.usage":              "Użycie: `!gem [png|svg|pdf] [light|dark] [momentum] [pionowy] [--dpi N] [--rozmiar SZERxWYS] [--waluta KOD]`, np. `!gem svg`, `!gem dark momentum`, `!gem --dpi 200`, `!gem pionowy`, `!gem --waluta USD`",
+		"gem.fetch_failed":       "nie udało się pobrać danych dla %s (%s). %s",
+		"gem.missing":            "⚠️ Brak danych dla: %s",
+		"gem.hint.timeout":       "Yahoo odpowiada zbyt wolno, spróbuj ponownie za minutę.",
+		"gem.hint.rate_limit":    "Yahoo ogranicza liczbę zapytań, spróbuj ponownie za kilka minut.",
+		"gem.hint.ticker":        "Sprawdź, czy symbol jest poprawny.",
+		"gem.hint.retry":         "Spróbuj ponownie za kilka minut.",
+		"compare.usage":          "Użycie: `!wykres TICKER... [zakres] [1d|1wk|1mo] [procent|indeks]` oraz opcje jak w `!gem`, np. `!wykres SPY QQQ VWCE.DE 2y`, `!wykres CNDX.L 6m indeks`, `!wykres EIMI.L 10y 1mo svg`",
+		"compare.title":          "Porównanie – %s",
+		"compare.footer":         "Yahoo Finance • %s",
+		"compare.interval.1d":    "Interwał dzienny",
+		"compare.interval.1wk":   "Interwał tygodniowy",
+		"compare.interval.1mo":   "Interwał miesięczny",
+		"compare.err.no_tickers": "podaj co najmniej jeden ticker",
+		"compare.err.too_many":   "najwyżej %d tickerów na jednym wykresie",
+		"compare.err.ticker":     "niepoprawny ticker: %s",
+		"compare.err.range":      "zakres podaj w miesiącach lub latach, np. 6m albo 2y (najwyżej %d lat)",
+		"gem.err.zero_base":      "wartość bazowa dla %s równa zero",
+		"gem.err.bad_return":     "nieprawidłowe dane zwrotu dla %s",
+		"gem.err.status":         "yahoo status %d dla %s",
+		"gem.err.no_results":     "brak wyników dla %s",
+		"gem.err.no_prices":      "brak danych cenowych dla %s",
+		"gem.err.length":         "niezgodna długość danych dla %s",
+
+		"weather.tomorrow_title":   "🌤️ Pogoda na jutro",
+		"weather.week_title":       "📅 Pogoda na 7 dni",
+		"weather.week_chart_title": "Prognoza 7 dni (linia ciągła: max, przerywana: min)",
+		"weather.hourly_title":     "🕐 %s: jutro godzina po godzinie",
+		"weather.hourly_header":    "godz  temp  opady  wiatr",
+		"weather.footer":           "Open-Meteo • prognoza na %s",
+		"weather.line":             "%s %s: %s, %.0f/%.0f%s, opady %.1f %s (%.0f%%), wiatr %.0f %s (porywy %.0f), UV %.0f, 🌅 %s 🌇 %s",
+		"weather.details":          "%s %s\n🌡️ %.0f / %.0f%s\n💧 %.1f %s (%.0f%%)\n💨 %.0f %s, porywy %.0f %s\n🔆 UV %.0f\n🌅 %s  🌇 %s",
+		"weather.fetch_failed":     "❌ Nie udało się pobrać prognozy",
+		"weather.not_found":        "❌ Nie znalazłem miejscowości %q",
+		"weather.location_unknown": "❌ Nie znalazłem miejscowości",
+		"weather.location_exists":  "✅ %s już jest na liście.",
+		"weather.location_added":   "✅ Dodano %s (%s, %.4f, %.4f)",
+		"weather.location_missing": "❌ Nie ma %q na liście!",
+		"weather.location_removed": "✅ Usunięto %s z listy.",
+		"weather.location_usage":   "❌ Użycie: !pogoda dodaj <miasto> albo !pogoda usun <miasto>",
+		"weather.no_locations":     "Brak lokalizacji! Dodaj je komendą !pogoda dodaj <miasto>",
+		"weather.locations_header": "**📍 Lokalizacje pogodowe:**\n",
+
+		"weather.code.unknown": "pogoda",
+		"weather.code.0":       "bezchmurnie",
+		"weather.code.1":       "przeważnie bezchmurnie",
+		"weather.code.2":       "częściowe zachmurzenie",
+		"weather.code.3":       "pochmurno",
+		"weather.code.45":      "mgła",
+		"weather.code.48":      "mgła osadzająca szadź",
+		"weather.code.51":      "lekka mżawka",
+		"weather.code.53":      "umiarkowana mżawka",
+		"weather.code.55":      "gęsta mżawka",
+		"weather.code.56":      "lekka marznąca mżawka",
+		"weather.code.57":      "gęsta marznąca mżawka",
+		"weather.code.61":      "słaby deszcz",
+		"weather.code.63":      "umiarkowany deszcz",
+		"weather.code.65":      "ulewny deszcz",
+		"weather.code.66":      "słaby marznący deszcz",
+		"weather.code.67":      "silny marznący deszcz",
+		"weather.code.71":      "słaby śnieg",
+		"weather.code.73":      "umiarkowany śnieg",
+		"weather.code.75":      "intensywny śnieg",
+		"weather.code.77":      "ziarna śniegu",
+		"weather.code.80":      "słabe przelotne opady",
+		"weather.code.81":      "umiarkowane przelotne opady",
+		"weather.code.82":      "gwałtowne przelotne opady",
+		"weather.code.85":      "słabe przelotne opady śniegu",
+		"weather.code.86":      "silne przelotne opady śniegu",
+		"weather.code.95":      "burza",
+		"weather.code.96":      "burza z lekkim gradem",
+		"weather.code.99":      "burza z silnym gradem",
+
+		"weekday.0":       "niedziela",
+		"weekday.1":       "poniedziałek",
+		"weekday.2":       "wtorek",
+		"weekday.3":       "środa",
+		"weekday.4":       "czwartek",
+		"weekday.5":       "piątek",
+		"weekday.6":       "sobota",
+		"weekday.short.0": "nd",
+		"weekday.short.1": "pon",
+		"weekday.short.2": "wt",
+		"weekday.short.3": "śr",
+		"weekday.short.4": "czw",
+		"weekday.short.5": "pt",
+		"weekday.short.6": "sob",
+
+		"subscription.already":          "✅ Już masz %s w swojej prognozie.",
+		"subscription.added":            "✅ Dodano %s do Twojej codziennej prognozy (%s).",
+		"subscription.none":             "❌ Nie masz subskrypcji pogody!",
+		"subscription.none_hint":        "Nie masz subskrypcji pogody! Zapisz się komendą !pogoda zapisz <miasto>",
+		"subscription.cancelled":        "✅ Wypisano z codziennej prognozy.",
+		"subscription.missing":          "❌ Nie masz %q w swojej prognozie!",
+		"subscription.removed":          "✅ Usunięto %s z Twojej prognozy.",
+		"subscription.subscribe_first":  "❌ Najpierw zapisz się: !pogoda zapisz <miasto>",
+		"subscription.delivery_usage":   "❌ Użycie: !pogoda dostawa dm|kanal",
+		"subscription.delivery_set":     "✅ Prognozę dostaniesz %s.",
+		"subscription.delivery_dm":      "w wiadomości prywatnej",
+		"subscription.delivery_channel": "na kanale <#%s>",
+		"subscription.info":             "**🌤️ Twoja prognoza:** %s\nDostawa: %s",
+
+		"alert.title":        "⚠️ **Ostrzeżenie pogodowe — %s**",
+		"alert.mroz":         "🥶 mróz do %.0f°C",
+		"alert.upal":         "🥵 upał do %.0f°C",
+		"alert.opady":        "🌧️ silne opady %.0f mm",
+		"alert.porywy":       "💨 porywy wiatru do %.0f km/h",
+		"alert.fetch_failed": "nie udało się pobrać prognozy: %s",
+
+		"thresholds.default":   "Domyślne progi: %s\nZmiana: !pogoda progi <miasto> mroz|upal|opady|porywy|burza <wartość>",
+		"thresholds.for":       "Progi dla %s: %s",
+		"thresholds.saved":     "✅ Progi dla %s: %s",
+		"thresholds.unknown":   "❌ Nieznany próg! Dostępne: mroz, upal, opady, porywy, burza",
+		"thresholds.bad_value": "❌ Nieprawidłowa wartość!",
+		"thresholds.describe":  "mróz ≤ %.0f°C, upał ≥ %.0f°C, opady ≥ %.0f mm, porywy ≥ %.0f km/h, burze %s",
+		"thresholds.on":        "wł.",
+		"thresholds.off":       "wył.",
+
+		"units.describe":    "temperatura %s, wiatr %s, opady %s",
+		"units.usage":       "❌ Użycie: !ustawienia pogoda [serwer] [temp C|F] [wiatr kmh|ms] [opady mm|cale] albo !ustawienia wykres [light|dark]",
+		"theme.current":     "🎨 Motyw wykresów serwera: **%s**",
+		"theme.usage":       "❌ Użycie: !ustawienia wykres [%s]",
+		"theme.set":         "✅ Motyw wykresów serwera: **%s**",
+		"units.guild_only":  "❌ Ustawienia serwera można zmienić tylko na serwerze!",
+		"units.current":     "**⚙️ Jednostki pogody**\nTwoje: %s\nSerwera: %s",
+		"units.pairs":       "❌ Podaj pary: <ustawienie> <wartość>, np. !ustawienia pogoda temp F wiatr ms",
+		"units.guild_saved": "✅ Jednostki serwera: %s",
+		"units.user_saved":  "✅ Twoje jednostki: %s",
+		"units.err.temp":    "temperatura: C albo F",
+		"units.err.wind":    "wiatr: kmh albo ms",
+		"units.err.precip":  "opady: mm albo cale",
+		"units.err.unknown": "nieznane ustawienie %q",
+
+		"job.cytat":            "Złota myśl dnia",
+		"job.gem":              "Miesięczny wykres ETF (ostatni dzień miesiąca)",
+		"job.pogoda":           "Prognoza pogody na jutro",
+		"job.ostrzezenia":      "Ostrzeżenia pogodowe dla zapisanych lokalizacji",
+		"job.alerty":           "Alerty cenowe (w godzinach sesji giełdy)",
+		"job.failed_title":     "🚨 **Zadanie %s nie powiodło się**\n",
+		"job.failed_scheduled": "Termin: %s\n",
+		"job.attempt_ok":       "ok",
+
+		"failures.channel": "✅ Awarie zadań będą zgłaszane na tym kanale.",
+		"failures.dm":      "✅ Awarie zadań będę zgłaszać Ci w wiadomości prywatnej.",
+		"failures.off":     "✅ Wyłączono zgłaszanie awarii zadań.",
+		"failures.usage":   "❌ Użycie: !awarie kanal|dm|off",
+
+		"schedule.header":          "**🗓️ Harmonogram zadań:**\n",
+		"schedule.entry":           "\n**%s** — %s\n`%s` (%s), następne: %s\n",
+		"schedule.disabled":        "wyłączone",
+		"schedule.usage":           "❌ Użycie: !harmonogram set <zadanie> <cron>",
+		"schedule.updated":         "✅ Zadanie **%s** ma nowy harmonogram: `%s`",
+		"schedule.next_run":        "\nNastępne uruchomienie: %s",
+		"schedule.err.timezone":    "nieznana strefa czasowa %q",
+		"schedule.err.spec":        "nieprawidłowy harmonogram %q: %v",
+		"schedule.err.catch_up":    "nieprawidłowe okno nadrabiania %q",
+		"schedule.err.backoff":     "nieprawidłowy odstęp ponowień %q",
+		"schedule.err.retries":     "liczba ponowień nie może być ujemna",
+		"schedule.err.unknown_job": "nie ma zadania %q",
+
+		"pricealert.usage":               "Użycie: `!alert TICKER > CENA`, `!alert TICKER < CENA` albo `!alert TICKER -5% 1d` (zmiana w ciągu N dni); dopisz `powtarzaj`, żeby alert wracał po każdym zadziałaniu",
+		"pricealert.added":               "✅ Alert #%d: %s (%s). Teraz: %.2f %s",
+		"pricealert.fired":               "🔔 <@%s> alert #%d: %s — teraz %.2f %s",
+		"pricealert.fired_change":        "🔔 <@%s> alert #%d: %s — zmiana %+.2f%%, teraz %.2f %s",
+		"pricealert.disarmed":            "Alert wyłączony, usuń go komendą !alerty usun %d",
+		"pricealert.rearm_hint":          "Alert zadziała ponownie, gdy warunek przestanie i znów zacznie być spełniony",
+		"pricealert.rule_change":         "%s %+g%% w ciągu %s",
+		"pricealert.status.armed":        "aktywny",
+		"pricealert.status.armed_repeat": "aktywny, powtarzany",
+		"pricealert.status.waiting":      "zadziałał %s, czeka na odwrócenie warunku",
+		"pricealert.status.disarmed":     "wyłączony, zadziałał %s",
+		"pricealert.none":                "Nie masz żadnych alertów cenowych! Dodaj je komendą !alert",
+		"pricealert.not_found":           "❌ Nie masz alertu o takim numerze!",
+		"pricealert.deleted":             "✅ Usunięto alert #%d",
+		"pricealert.delete_hint":         "Usuwanie: !alerty usun <numer>",
+		"pricealert.too_many":            "❌ Możesz mieć najwyżej %d alertów cenowych",
+		"pricealert.ticker_failed":       "❌ Nie udało się sprawdzić tickera: %s",
+		"pricealert.fetch_failed":        "nie udało się pobrać notowań dla: %s",
+		"pricealert.err.incomplete":      "podaj ticker i warunek",
+		"pricealert.err.price":           "nieprawidłowa cena %q",
+		"pricealert.err.change":          "nieprawidłowa zmiana %q, np. -5%%",
+		"pricealert.err.window":          "okno podaj w dniach, np. 1d albo 5d (najwyżej %d)",
+		"pricealert.err.unknown":         "nie rozumiem %q",
+
+		"reminder.fired":           "⏰ <@%s> przypomnienie: %s",
+		"reminder.added":           "✅ Przypomnienie #%d: %s — %s",
+		"reminder.examples":        "Przykłady: !przypomnij 2h kupić ETF, !przypomnij 2026-11-01 10:00 rebalans, !przypomnij co piątek 16:00 raport",
+		"reminder.schedule_failed": "❌ Nie udało się zaplanować przypomnienia",
+		"reminder.not_found":       "❌ Nie masz przypomnienia o takim numerze!",
+		"reminder.cancelled":       "✅ Anulowano przypomnienie #%d",
+		"reminder.none":            "Nie masz żadnych przypomnień! Dodaj je komendą !przypomnij",
+		"reminder.cancel_hint":     "Anulowanie: !przypomnienia usun <numer>",
+		"reminder.daily":           "codziennie %02d:%02d",
+		"reminder.weekly":          "co %s %02d:%02d",
+		"reminder.err.no_time":     "brak czasu przypomnienia",
+		"reminder.err.no_text":     "brak treści przypomnienia",
+		"reminder.err.no_day":      "podaj dzień, np. co piątek 16:00",
+		"reminder.err.unknown":     "nie rozumiem %q",
+		"reminder.err.bad_time":    "nie rozumiem czasu %q",
+		"reminder.err.past":        "ten termin już minął",
+		"reminder.err.bad_date":    "nie ma takiej daty: %s",
+		"reminder.err.too_far":     "przypomnienie można ustawić najwyżej %d lat naprzód",
+	},
+	plurals: map[string][]string{
+		"quote.footer":           {"%d złota myśl w kolekcji", "%d złote myśli w kolekcji", "%d złotych myśli w kolekcji"},
+		"job.failed_attempts":    {"%d próba\n", "%d próby\n", "%d prób\n"},
+		"compare.range.months":   {"%d miesiąc", "%d miesiące", "%d miesięcy"},
+		"compare.range.years":    {"%d rok", "%d lata", "%d lat"},
+		"pricealert.list_header": {"**🔔 Masz %d alert cenowy:**\n", "**🔔 Masz %d alerty cenowe:**\n", "**🔔 Masz %d alertów cenowych:**\n"},
+		"pricealert.days":        {"%d dnia", "%d dni", "%d dni"},
+		"reminder.list_header":   {"**⏰ Masz %d przypomnienie:**\n", "**⏰ Masz %d przypomnienia:**\n", "**⏰ Masz %d przypomnień:**\n"},
+	},
+}
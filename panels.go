@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Wiersze siatki zajmowane przez panele: obszar danych głównego jest dwa razy wyższy od pomocniczych.
+const (
+	gemMainPanelWeight = 2
+	gemSidePanelWeight = 1
+)
+
+// Formaty etykiet serii: stopa zwrotu w procentach albo poziom indeksu (start = 100).
+const (
+	returnLabelFormat = "%s %+0.2f%%"
+	indexLabelFormat  = "%s %.1f"
+)
+
+// newChartPanel tworzy panel ze wspólnym wyglądem: motyw, siatka, oś X z miesiącami
+// i ukryta lewa oś Y (wartości pokazuje prawa oś z rightSideAnnotations).
+// Domyślnie oś Y jest w procentach; inną podziałkę ustawia się w p.Y.Tick.Marker przed rightAxis.
+func newChartPanel(theme chartTheme, loc *time.Location, xMin, xMax, yMin, yMax float64) *plot.Plot {
+	p := plot.New()
+	theme.apply(p)
+	p.X.Tick.Marker = monthTicks{Loc: loc, Format: "Jan 2006"}
+	p.Y.Tick.Marker = percentTicks{}
+	p.X.Min, p.X.Max = xMin, xMax
+	p.Y.Min, p.Y.Max = yMin, yMax
+	p.Add(theme.grid())
+	return p
+}
+
+// rightAxis zwraca prawą oś z podziałką panelu i etykietami serii; ukrywa przy tym lewą oś Y.
+func rightAxis(theme chartTheme, p *plot.Plot, labels []seriesLabel) rightSideAnnotations {
+	tickStyle := p.Y.Tick.Label
+	tickStyle.XAlign = draw.XLeft
+	axisLineStyle := draw.LineStyle{Color: theme.Axis, Width: theme.AxisWidth}
+
+	p.Y.Tick.Label.Font.Size = 0
+	p.Y.Tick.Label.Color = color.Transparent
+	p.Y.Tick.Length = 0
+	p.Y.Tick.LineStyle.Width = 0
+	p.Y.LineStyle.Width = 0
+
+	return rightSideAnnotations{
+		Ticker:        p.Y.Tick.Marker,
+		TickStyle:     tickStyle,
+		LabelStyle:    tickStyle,
+		TickLength:    vg.Points(4),
+		TickPadding:   vg.Points(6),
+		LabelSpacing:  vg.Points(20),
+		Gap:           vg.Points(2),
+		AxisLineStyle: axisLineStyle,
+		TickLineStyle: axisLineStyle,
+		Labels:        labels,
+	}
+}
+
+// hideXLabels chowa podpisy osi X w panelach nad dolnym; siatka miesięcy zostaje.
+func hideXLabels(p *plot.Plot) {
+	p.X.Label.Text = ""
+	p.X.Tick.Label.Font.Size = 0
+	p.X.Tick.Label.Color = color.Transparent
+}
+
+// seriesLine rysuje serię w podanym kolorze; punkty NaN (brak historii) są pomijane.
+func seriesLine(theme chartTheme, col color.Color, times []time.Time, series []float64) (*plotter.Line, error) {
+	pts := make(plotter.XYs, 0, len(series))
+	for i, v := range series {
+		if !isFinite(v) {
+			continue
+		}
+		pts = append(pts, plotter.XY{X: float64(times[i].Unix()), Y: v})
+	}
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return nil, err
+	}
+	line.Color = col
+	line.Width = theme.LineWidth
+	return line, nil
+}
+
+// lastFinite zwraca ostatnią skończoną wartość serii.
+func lastFinite(series []float64) (float64, bool) {
+	for i := len(series) - 1; i >= 0; i-- {
+		if isFinite(series[i]) {
+			return series[i], true
+		}
+	}
+	return 0, false
+}
+
+// drawdownSeries liczy spadek od bieżącego szczytu w procentach (0 na szczycie).
+func drawdownSeries(values []float64) []float64 {
+	out := make([]float64, len(values))
+	peak := math.Inf(-1)
+	for i, v := range values {
+		if v > peak {
+			peak = v
+		}
+		out[i] = (v/peak - 1) * 100
+	}
+	return out
+}
+
+// momentumSeries liczy 12-miesięczną stopę zwrotu dla punktów od indeksu from.
+// Punkty bez pełnego roku historii dostają NaN.
+func momentumSeries(times []time.Time, values []float64, from int) []float64 {
+	out := make([]float64, 0, len(times)-from)
+	for i := from; i < len(times); i++ {
+		target := times[i].AddDate(-1, 0, 0)
+		// ostatnia notowana sesja nie później niż rok wcześniej
+		j := sort.Search(len(times), func(k int) bool { return times[k].After(target) }) - 1
+		if j < 0 || !isFinite(values[j]) || values[j] == 0 {
+			out = append(out, math.NaN())
+			continue
+		}
+		out = append(out, (values[i]/values[j]-1)*100)
+	}
+	return out
+}
+
+// seriesRange zwraca zakres osi Y obejmujący wszystkie serie i wartość ref
+// (0 dla procentów, 100 dla indeksu), z marginesem.
+func seriesRange(series map[string][]float64, ref, minSpan float64) (float64, float64) {
+	lo, hi := ref, ref
+	for _, s := range series {
+		for _, v := range s {
+			if !isFinite(v) {
+				continue
+			}
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+	if hi-lo < minSpan {
+		lo -= (minSpan - (hi - lo)) / 2
+		hi = lo + minSpan
+	}
+	margin := (hi - lo) * 0.1
+	return lo - margin, hi + margin
+}
+
+// panelLabels buduje etykiety prawej osi z ostatnich wartości serii;
+// format dostaje ticker i wartość, np. "%s %+0.2f%%".
+func panelLabels(colors map[string]color.Color, tickers []string, series map[string][]float64, format string) []seriesLabel {
+	labels := make([]seriesLabel, 0, len(tickers))
+	for _, ticker := range tickers {
+		last, ok := lastFinite(series[ticker])
+		if !ok {
+			continue
+		}
+		labels = append(labels, seriesLabel{
+			Text:  fmt.Sprintf(format, ticker, last),
+			Value: last,
+			Color: colors[ticker],
+		})
+	}
+	return labels
+}
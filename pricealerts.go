@@ -0,0 +1,422 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	maxPriceAlertsPerUser = 20
+	maxPriceAlertWindow   = 365
+)
+
+// Rodzaje alertów cenowych: przekroczenie progu ceny albo zmiana w oknie WindowDays.
+const (
+	priceAlertAbove  = ">"
+	priceAlertBelow  = "<"
+	priceAlertChange = "%"
+)
+
+type PriceAlert struct {
+	ID        int    `json:"id"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	GuildID   string `json:"guild_id,omitempty"`
+	Ticker    string `json:"ticker"`
+	Op        string `json:"op"`
+	// Value to próg ceny w walucie notowań albo zmiana w procentach (ujemna oznacza spadek).
+	Value      float64 `json:"value"`
+	WindowDays int     `json:"window_days,omitempty"`
+	// Repeat uzbraja alert ponownie, gdy warunek przestanie być spełniony;
+	// bez niego alert po zadziałaniu zostaje wyłączony.
+	Repeat    bool      `json:"repeat,omitempty"`
+	Triggered bool      `json:"triggered,omitempty"`
+	LastFired time.Time `json:"last_fired,omitzero"`
+	Created   time.Time `json:"created"`
+}
+
+// armed mówi, czy alert może zadziałać przy najbliższym sprawdzeniu.
+func (a PriceAlert) armed() bool {
+	return !a.Triggered
+}
+
+// watched mówi, czy poller ma sprawdzać alert; wyłączone jednorazowe alerty pomija.
+func (a PriceAlert) watched() bool {
+	return a.Repeat || !a.Triggered
+}
+
+var priceAlertWindowRe = regexp.MustCompile(`^(\d+)d$`)
+
+// parsePriceAlert czyta "TICKER > 1200", "TICKER < 950,5" albo "TICKER -5% 1d",
+// z opcjonalnym "powtarzaj" na końcu.
+func parsePriceAlert(args string) (PriceAlert, error) {
+	fields := strings.Fields(args)
+	a := PriceAlert{}
+	if n := len(fields); n > 0 && strings.ToLower(fields[n-1]) == "powtarzaj" {
+		a.Repeat = true
+		fields = fields[:n-1]
+	}
+	if len(fields) < 2 {
+		return a, trError("pricealert.err.incomplete")
+	}
+	a.Ticker = strings.ToUpper(fields[0])
+	if !compareTickerPattern.MatchString(a.Ticker) {
+		return a, trError("compare.err.ticker", fields[0])
+	}
+
+	rule := fields[1:]
+	// "> 1200" i ">1200" to to samo
+	if op := rule[0][:1]; (op == priceAlertAbove || op == priceAlertBelow) && len(rule[0]) > 1 {
+		rule = append([]string{op, rule[0][1:]}, rule[1:]...)
+	}
+
+	switch op := rule[0]; {
+	case op == priceAlertAbove || op == priceAlertBelow:
+		if len(rule) != 2 {
+			return a, trError("pricealert.err.incomplete")
+		}
+		price, err := strconv.ParseFloat(strings.ReplaceAll(rule[1], ",", "."), 64)
+		if err != nil || price <= 0 || !isFinite(price) {
+			return a, trError("pricealert.err.price", rule[1])
+		}
+		a.Op, a.Value = op, price
+	case strings.HasSuffix(op, "%"):
+		change, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSuffix(op, "%"), ",", "."), 64)
+		if err != nil || change == 0 || !isFinite(change) {
+			return a, trError("pricealert.err.change", op)
+		}
+		a.Op, a.Value, a.WindowDays = priceAlertChange, change, 1
+		if len(rule) > 2 {
+			return a, trError("pricealert.err.unknown", rule[2])
+		}
+		if len(rule) == 2 {
+			m := priceAlertWindowRe.FindStringSubmatch(strings.ToLower(rule[1]))
+			if m == nil {
+				return a, trError("pricealert.err.window", maxPriceAlertWindow)
+			}
+			days, _ := strconv.Atoi(m[1])
+			if days < 1 || days > maxPriceAlertWindow {
+				return a, trError("pricealert.err.window", maxPriceAlertWindow)
+			}
+			a.WindowDays = days
+		}
+	default:
+		return a, trError("pricealert.err.unknown", op)
+	}
+	return a, nil
+}
+
+// evaluate sprawdza alert na notowaniach dziennych. Zwraca ostatnią cenę,
+// zmianę w procentach względem sesji sprzed WindowDays dni (tylko dla alertów zmiany)
+// i to, czy warunek jest spełniony.
+func (a PriceAlert) evaluate(times []time.Time, values []float64) (price, change float64, hit, ok bool) {
+	last := len(values) - 1
+	for last >= 0 && !isFinite(values[last]) {
+		last--
+	}
+	if last < 0 {
+		return 0, 0, false, false
+	}
+	price = values[last]
+
+	switch a.Op {
+	case priceAlertAbove:
+		return price, 0, price > a.Value, true
+	case priceAlertBelow:
+		return price, 0, price < a.Value, true
+	case priceAlertChange:
+		target := times[last].AddDate(0, 0, -a.WindowDays)
+		// ostatnia sesja nie później niż WindowDays dni przed ostatnim notowaniem
+		j := sort.Search(len(times), func(k int) bool { return times[k].After(target) }) - 1
+		if j < 0 || !isFinite(values[j]) || values[j] == 0 {
+			return price, 0, false, false
+		}
+		change = (price/values[j] - 1) * 100
+		if a.Value < 0 {
+			return price, change, change <= a.Value, true
+		}
+		return price, change, change >= a.Value, true
+	}
+	return price, 0, false, false
+}
+
+// describe opisuje warunek alertu, np. "CNDX.L > 1200" albo "EIMI.L -5% w ciągu 1 dnia".
+func (a PriceAlert) describe(lang string) string {
+	if a.Op == priceAlertChange {
+		return tr(lang, "pricealert.rule_change", a.Ticker, a.Value, trn(lang, "pricealert.days", a.WindowDays, a.WindowDays))
+	}
+	return fmt.Sprintf("%s %s %s", a.Ticker, a.Op, strconv.FormatFloat(a.Value, 'f', -1, 64))
+}
+
+func (a PriceAlert) status(lang string) string {
+	fired := ""
+	if !a.LastFired.IsZero() {
+		loc, err := time.LoadLocation(defaultTimezone)
+		if err != nil {
+			loc = time.Local
+		}
+		fired = a.LastFired.In(loc).Format("2006-01-02 15:04")
+	}
+	switch {
+	case a.armed() && a.Repeat:
+		return tr(lang, "pricealert.status.armed_repeat")
+	case a.armed():
+		return tr(lang, "pricealert.status.armed")
+	case a.Repeat:
+		return tr(lang, "pricealert.status.waiting", fired)
+	}
+	return tr(lang, "pricealert.status.disarmed", fired)
+}
+
+func addPriceAlert(a PriceAlert) (PriceAlert, bool) {
+	configMu.Lock()
+	count := 0
+	for _, existing := range config.PriceAlerts {
+		if existing.UserID == a.UserID {
+			count++
+		}
+	}
+	if count >= maxPriceAlertsPerUser {
+		configMu.Unlock()
+		return a, false
+	}
+	config.NextPriceAlertID++
+	a.ID = config.NextPriceAlertID
+	config.PriceAlerts = append(config.PriceAlerts, a)
+	configMu.Unlock()
+	saveConfig()
+	return a, true
+}
+
+// removePriceAlert usuwa alert użytkownika; false, gdy nie ma takiego alertu albo należy do kogoś innego.
+func removePriceAlert(id int, userID string) bool {
+	configMu.Lock()
+	removed := false
+	kept := config.PriceAlerts[:0]
+	for _, a := range config.PriceAlerts {
+		if a.ID == id && a.UserID == userID {
+			removed = true
+			continue
+		}
+		kept = append(kept, a)
+	}
+	config.PriceAlerts = kept
+	configMu.Unlock()
+	if removed {
+		saveConfig()
+	}
+	return removed
+}
+
+func listPriceAlerts(userID string) []PriceAlert {
+	configMu.Lock()
+	defer configMu.Unlock()
+	out := make([]PriceAlert, 0, len(config.PriceAlerts))
+	for _, a := range config.PriceAlerts {
+		if userID == "" || a.UserID == userID {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// updatePriceAlerts zapisuje nowy stan alertów; pomija te, które w międzyczasie usunięto.
+func updatePriceAlerts(updated map[int]PriceAlert) {
+	if len(updated) == 0 {
+		return
+	}
+	configMu.Lock()
+	for i, a := range config.PriceAlerts {
+		if u, ok := updated[a.ID]; ok {
+			config.PriceAlerts[i].Triggered = u.Triggered
+			config.PriceAlerts[i].LastFired = u.LastFired
+		}
+	}
+	configMu.Unlock()
+	saveConfig()
+}
+
+// checkPriceAlerts to zadanie crona: sprawdza alerty tickerów, których giełda ma teraz sesję,
+// oznacza autorów zadziałanych alertów i wyłącza je albo czeka na ponowne uzbrojenie.
+func checkPriceAlerts(ctx context.Context, s *discordgo.Session, now time.Time) error {
+	var alerts []PriceAlert
+	var tickers []string
+	maxWindow := 1
+	for _, a := range listPriceAlerts("") {
+		if !a.watched() || !isMarketOpen(a.Ticker, now) {
+			continue
+		}
+		alerts = append(alerts, a)
+		if !slices.Contains(tickers, a.Ticker) {
+			tickers = append(tickers, a.Ticker)
+		}
+		maxWindow = max(maxWindow, a.WindowDays)
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	// zapas na weekendy i święta przed początkiem okna
+	start := now.AddDate(0, 0, -maxWindow-10)
+	byTicker := make(map[string]alertQuotes, len(tickers))
+	var missing []string
+	for _, ticker := range tickers {
+		q, err := fetchAlertQuotes(ctx, ticker, start, now)
+		if err != nil {
+			slog.Warn("brak notowań dla alertów", "job", "alerty", "ticker", ticker, "error", err)
+			missing = append(missing, ticker)
+			continue
+		}
+		if len(q.Times) == 0 || !tradedToday(ticker, q.Times[len(q.Times)-1], now) {
+			// święto albo sesja jeszcze bez notowania: wczorajsza cena mogłaby zadziałać drugi raz
+			slog.Debug("brak dzisiejszego notowania, pomijam", "job", "alerty", "ticker", ticker)
+			continue
+		}
+		byTicker[ticker] = q
+	}
+
+	updated := make(map[int]PriceAlert)
+	for _, a := range alerts {
+		q, ok := byTicker[a.Ticker]
+		if !ok {
+			continue
+		}
+		price, change, hit, ok := a.evaluate(q.Times, q.Values)
+		if !ok {
+			continue
+		}
+		switch {
+		case hit && a.armed():
+			a.Triggered = true
+			a.LastFired = now
+			updated[a.ID] = a
+			if err := deliverPriceAlert(s, a, price, change, q.Currency); err != nil {
+				slog.Error("błąd wysyłki alertu cenowego", "job", "alerty", "alert", a.ID, "channel", a.ChannelID, "error", err)
+			}
+		case !hit && a.Triggered && a.Repeat:
+			a.Triggered = false
+			updated[a.ID] = a
+			slog.Debug("alert cenowy uzbrojony ponownie", "alert", a.ID, "ticker", a.Ticker)
+		}
+	}
+	updatePriceAlerts(updated)
+
+	if len(missing) > 0 {
+		return trError("pricealert.fetch_failed", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// alertQuotes to notowania jednego tickera bez brakujących sesji.
+type alertQuotes struct {
+	Times    []time.Time
+	Values   []float64
+	Currency string
+}
+
+// fetchAlertQuotes pobiera każdy ticker osobno. Wspólna tabela z fetchPriceTable uzupełnia
+// brakujące sesje poprzednią ceną, przez co zmiana dzienna wychodziłaby 0%.
+func fetchAlertQuotes(ctx context.Context, ticker string, start, end time.Time) (alertQuotes, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, marketFetchTimeout)
+	defer cancel()
+	started := time.Now()
+	series, err := fetchYahooSeries(reqCtx, yahooClient, ticker, start, end, "1d")
+	observeAPI("yahoo", time.Since(started), err)
+	if err != nil {
+		return alertQuotes{}, &tickerFetchError{Ticker: ticker, Err: err}
+	}
+	q := alertQuotes{Currency: series.Currency}
+	for i, ts := range series.Timestamps {
+		if isFinite(series.Closes[i]) {
+			q.Times = append(q.Times, time.Unix(ts, 0))
+			q.Values = append(q.Values, series.Closes[i])
+		}
+	}
+	return q, nil
+}
+
+func deliverPriceAlert(s *discordgo.Session, a PriceAlert, price, change float64, currency string) error {
+	lang := langFor(a.GuildID)
+	var b strings.Builder
+	if a.Op == priceAlertChange {
+		b.WriteString(tr(lang, "pricealert.fired_change", a.UserID, a.ID, a.describe(lang), change, price, currency))
+	} else {
+		b.WriteString(tr(lang, "pricealert.fired", a.UserID, a.ID, a.describe(lang), price, currency))
+	}
+	b.WriteString("\n")
+	if a.Repeat {
+		b.WriteString(tr(lang, "pricealert.rearm_hint"))
+	} else {
+		b.WriteString(tr(lang, "pricealert.disarmed", a.ID))
+	}
+	_, err := s.ChannelMessageSend(a.ChannelID, b.String())
+	return err
+}
+
+// handlePriceAlertCommand dodaje alert po sprawdzeniu, że Yahoo zna ticker; potwierdzenie podaje bieżącą cenę.
+func handlePriceAlertCommand(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args string) {
+	lang := langFor(m.GuildID)
+	a, err := parsePriceAlert(args)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "❌ "+localizeError(lang, err)+"\n"+tr(lang, "pricealert.usage"))
+		return
+	}
+
+	now := time.Now()
+	q, err := fetchAlertQuotes(ctx, a.Ticker, now.AddDate(0, 0, -a.WindowDays-10), now)
+	if err != nil {
+		msgLogger(m).Warn("nie udało się sprawdzić tickera alertu", "ticker", a.Ticker, "error", err)
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "pricealert.ticker_failed", describeChartError(lang, err)))
+		return
+	}
+	price, _, _, _ := a.evaluate(q.Times, q.Values)
+
+	a.UserID = m.Author.ID
+	a.ChannelID = m.ChannelID
+	a.GuildID = m.GuildID
+	a.Created = time.Now()
+	a, ok := addPriceAlert(a)
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "pricealert.too_many", maxPriceAlertsPerUser))
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, tr(lang, "pricealert.added", a.ID, a.describe(lang), a.status(lang), price, q.Currency))
+}
+
+func handlePriceAlertsCommand(s *discordgo.Session, m *discordgo.MessageCreate, args string) {
+	lang := langFor(m.GuildID)
+	fields := strings.Fields(args)
+	if len(fields) == 2 && (fields[0] == "usun" || fields[0] == "anuluj") {
+		id, err := strconv.Atoi(strings.TrimPrefix(fields[1], "#"))
+		if err != nil || !removePriceAlert(id, m.Author.ID) {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "pricealert.not_found"))
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "pricealert.deleted", id))
+		return
+	}
+
+	alerts := listPriceAlerts(m.Author.ID)
+	if len(alerts) == 0 {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "pricealert.none"))
+		return
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID < alerts[j].ID })
+
+	var b strings.Builder
+	b.WriteString(trn(lang, "pricealert.list_header", len(alerts), len(alerts)))
+	for _, a := range alerts {
+		b.WriteString(fmt.Sprintf("\n#%d — %s — %s", a.ID, a.describe(lang), a.status(lang)))
+	}
+	b.WriteString("\n\n" + tr(lang, "pricealert.delete_hint"))
+	s.ChannelMessageSend(m.ChannelID, b.String())
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParsePriceAlert(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   PriceAlert
+		errKey string
+	}{
+		{input: "cndx.l > 1200", want: PriceAlert{Ticker: "CNDX.L", Op: ">", Value: 1200}},
+		{input: "CNDX.L >1200", want: PriceAlert{Ticker: "CNDX.L", Op: ">", Value: 1200}},
+		{input: "SPY < 950,5 powtarzaj", want: PriceAlert{Ticker: "SPY", Op: "<", Value: 950.5, Repeat: true}},
+		{input: "EIMI.L -5%", want: PriceAlert{Ticker: "EIMI.L", Op: "%", Value: -5, WindowDays: 1}},
+		{input: "EIMI.L +7,5% 30d", want: PriceAlert{Ticker: "EIMI.L", Op: "%", Value: 7.5, WindowDays: 30}},
+		{input: "EURPLN=X 2% 5D Powtarzaj", want: PriceAlert{Ticker: "EURPLN=X", Op: "%", Value: 2, WindowDays: 5, Repeat: true}},
+
+		{input: "SPY", errKey: "pricealert.err.incomplete"},
+		{input: "SPY >", errKey: "pricealert.err.incomplete"},
+		{input: "SPY > 10 20", errKey: "pricealert.err.incomplete"},
+		{input: "S&P > 10", errKey: "compare.err.ticker"},
+		{input: "SPY > abc", errKey: "pricealert.err.price"},
+		{input: "SPY > -5", errKey: "pricealert.err.price"},
+		{input: "SPY > 0", errKey: "pricealert.err.price"},
+		{input: "SPY 0%", errKey: "pricealert.err.change"},
+		{input: "SPY x%", errKey: "pricealert.err.change"},
+		{input: "SPY 5% 0d", errKey: "pricealert.err.window"},
+		{input: "SPY 5% 400d", errKey: "pricealert.err.window"},
+		{input: "SPY 5% tydzień", errKey: "pricealert.err.window"},
+		{input: "SPY 5% 1d extra", errKey: "pricealert.err.unknown"},
+		{input: "SPY = 5", errKey: "pricealert.err.unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parsePriceAlert(tt.input)
+			if tt.errKey != "" {
+				var le *localizedError
+				if !errors.As(err, &le) || le.Key != tt.errKey {
+					t.Fatalf("err = %v, want %s", err, tt.errKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceAlertEvaluate(t *testing.T) {
+	days := sessionTimes(time.Date(2026, 10, 5, 13, 30, 0, 0, time.UTC), 8)
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		alert  PriceAlert
+		values []float64
+		price  float64
+		change float64
+		hit    bool
+		ok     bool
+	}{
+		{"powyżej progu", PriceAlert{Op: ">", Value: 100}, []float64{90, 95, 99, 100, 101, 102, 103, 104}, 104, 0, true, true},
+		{"równo z progiem", PriceAlert{Op: ">", Value: 104}, []float64{90, 95, 99, 100, 101, 102, 103, 104}, 104, 0, false, true},
+		{"poniżej progu", PriceAlert{Op: "<", Value: 100}, []float64{110, 105, 101, 100, 99, 98, 97, 96}, 96, 0, true, true},
+		{"nad progiem spadku", PriceAlert{Op: "<", Value: 90}, []float64{110, 105, 101, 100, 99, 98, 97, 96}, 96, 0, false, true},
+		{"ostatnia cena NaN", PriceAlert{Op: ">", Value: 100}, []float64{90, 95, 99, 100, 101, 102, 103, nan}, 103, 0, true, true},
+		{"brak cen", PriceAlert{Op: ">", Value: 100}, []float64{nan, nan, nan, nan, nan, nan, nan, nan}, 0, 0, false, false},
+		{"spadek dzienny", PriceAlert{Op: "%", Value: -5, WindowDays: 1}, []float64{100, 100, 100, 100, 100, 100, 100, 94}, 94, -6, true, true},
+		{"za mały spadek", PriceAlert{Op: "%", Value: -5, WindowDays: 1}, []float64{100, 100, 100, 100, 100, 100, 100, 96}, 96, -4, false, true},
+		{"wzrost nie liczy się jako spadek", PriceAlert{Op: "%", Value: -5, WindowDays: 1}, []float64{100, 100, 100, 100, 100, 100, 100, 110}, 110, 10, false, true},
+		{"wzrost tygodniowy", PriceAlert{Op: "%", Value: 10, WindowDays: 7}, []float64{100, 150, 150, 150, 150, 150, 150, 110}, 110, 10, true, true},
+		{"okno dłuższe niż dane", PriceAlert{Op: "%", Value: 10, WindowDays: 30}, []float64{100, 150, 150, 150, 150, 150, 150, 110}, 110, 0, false, false},
+		{"brak ceny na początku okna", PriceAlert{Op: "%", Value: 10, WindowDays: 1}, []float64{100, 100, 100, 100, 100, 100, nan, 110}, 110, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, change, hit, ok := tt.alert.evaluate(days, tt.values)
+			if price != tt.price || math.Abs(change-tt.change) > 1e-9 || hit != tt.hit || ok != tt.ok {
+				t.Errorf("evaluate = (%v, %v, %v, %v), want (%v, %v, %v, %v)",
+					price, change, hit, ok, tt.price, tt.change, tt.hit, tt.ok)
+			}
+		})
+	}
+}
+
+func TestIsMarketOpen(t *testing.T) {
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		ticker string
+		now    time.Time
+		want   bool
+	}{
+		// środa 14.10: Nowy Jork UTC-4, Londyn UTC+1, Warszawa UTC+2
+		{"SPY", utc(time.October, 14, 13, 29), false},
+		{"SPY", utc(time.October, 14, 13, 30), true},
+		{"SPY", utc(time.October, 14, 19, 59), true},
+		{"SPY", utc(time.October, 14, 20, 0), false},
+		{"CNDX.L", utc(time.October, 14, 6, 59), false},
+		{"CNDX.L", utc(time.October, 14, 7, 0), true},
+		{"CNDX.L", utc(time.October, 14, 15, 29), true},
+		{"CNDX.L", utc(time.October, 14, 15, 30), false},
+		{"PKO.WA", utc(time.October, 14, 7, 0), true},
+		{"PKO.WA", utc(time.October, 14, 15, 0), false},
+		// poniedziałek 26.10: Europa już na czasie zimowym, USA jeszcze na letnim
+		{"CNDX.L", utc(time.October, 26, 7, 30), false},
+		{"CNDX.L", utc(time.October, 26, 8, 0), true},
+		{"PKO.WA", utc(time.October, 26, 15, 30), true},
+		{"SPY", utc(time.October, 26, 13, 30), true},
+		// piątek wieczorem w Nowym Jorku to już sobota w UTC
+		{"SPY", utc(time.October, 17, 0, 30), false},
+		{"SPY", utc(time.October, 16, 19, 0), true},
+		// weekend
+		{"SPY", utc(time.October, 17, 15, 0), false},
+		{"CNDX.L", utc(time.October, 18, 10, 0), false},
+		// kursy walut i nieznane giełdy: cały dzień roboczy
+		{"EURPLN=X", utc(time.October, 14, 2, 0), true},
+		{"EURPLN=X", utc(time.October, 17, 12, 0), false},
+		{"7203.T", utc(time.October, 14, 23, 0), true},
+	}
+	for _, tt := range tests {
+		if got := isMarketOpen(tt.ticker, tt.now); got != tt.want {
+			t.Errorf("isMarketOpen(%s, %s) = %v, want %v", tt.ticker, tt.now.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
+
+func TestCheckPriceAlerts(t *testing.T) {
+	useTempConfig(t)
+	s, discord := newFakeSession(t)
+	yesterday := time.Date(2026, 10, 13, 13, 30, 0, 0, time.UTC)
+	today := yesterday.AddDate(0, 0, 1)
+	// 11:00 w Nowym Jorku, sesja trwa
+	now := time.Date(2026, 10, 14, 15, 0, 0, 0, time.UTC)
+	yahoo := useFakeYahoo(t, nil)
+	setPrices := func(times []time.Time, closes ...float64) {
+		yahoo.mu.Lock()
+		yahoo.series = map[string]fakeSeries{"SPY": {Currency: "USD", Times: times, Closes: closes}}
+		yahoo.mu.Unlock()
+	}
+
+	once, _ := addPriceAlert(PriceAlert{UserID: "u1", ChannelID: "c1", Ticker: "SPY", Op: ">", Value: 100})
+	repeat, _ := addPriceAlert(PriceAlert{UserID: "u1", ChannelID: "c1", Ticker: "SPY", Op: ">", Value: 100, Repeat: true})
+	drop, _ := addPriceAlert(PriceAlert{UserID: "u1", ChannelID: "c1", Ticker: "SPY", Op: "%", Value: -5, WindowDays: 1})
+
+	steps := []struct {
+		name      string
+		times     []time.Time
+		closes    []float64
+		sent      int
+		triggered map[int]bool
+	}{
+		{"przekroczenie progu", []time.Time{yesterday, today}, []float64{110, 105}, 2,
+			map[int]bool{once.ID: true, repeat.ID: true, drop.ID: false}},
+		{"bez powtórki przy tym samym stanie", []time.Time{yesterday, today}, []float64{110, 106}, 2,
+			map[int]bool{once.ID: true, repeat.ID: true, drop.ID: false}},
+		{"spadek uzbraja powtarzany i odpala zmianę", []time.Time{yesterday, today}, []float64{110, 95}, 3,
+			map[int]bool{once.ID: true, repeat.ID: false, drop.ID: true}},
+		{"święto: brak dzisiejszego notowania", []time.Time{yesterday.AddDate(0, 0, -1), yesterday}, []float64{90, 120}, 3,
+			map[int]bool{once.ID: true, repeat.ID: false, drop.ID: true}},
+		{"ponowne przekroczenie", []time.Time{yesterday, today}, []float64{110, 101}, 4,
+			map[int]bool{once.ID: true, repeat.ID: true, drop.ID: true}},
+	}
+	for _, step := range steps {
+		setPrices(step.times, step.closes...)
+		if err := checkPriceAlerts(context.Background(), s, now); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		discord.mu.Lock()
+		sent := len(discord.messages)
+		discord.mu.Unlock()
+		if sent != step.sent {
+			t.Errorf("%s: wysłano %d, want %d", step.name, sent, step.sent)
+		}
+		for _, a := range listPriceAlerts("") {
+			if a.Triggered != step.triggered[a.ID] {
+				t.Errorf("%s: alert #%d Triggered = %v, want %v", step.name, a.ID, a.Triggered, step.triggered[a.ID])
+			}
+		}
+	}
+
+	// giełda zamknięta: nic nie jest pobierane
+	yahoo.mu.Lock()
+	requested := len(yahoo.requested)
+	yahoo.mu.Unlock()
+	if err := checkPriceAlerts(context.Background(), s, now.Add(6*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if len(yahoo.requested) != requested {
+		t.Errorf("zapytania po zamknięciu sesji: %d, want %d", len(yahoo.requested), requested)
+	}
+}
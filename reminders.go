@@ -0,0 +1,574 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/robfig/cron/v3"
+)
+
+type Reminder struct {
+	ID        int       `json:"id"`
+	UserID    string    `json:"user_id"`
+	ChannelID string    `json:"channel_id"`
+	Text      string    `json:"text"`
+	At        time.Time `json:"at,omitzero"`
+	// Spec to harmonogram cron dla przypomnień cyklicznych, np. "0 16 * * 5".
+	Spec    string    `json:"spec,omitempty"`
+	Label   string    `json:"label,omitempty"`
+	DM      bool      `json:"dm,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+func (r Reminder) recurring() bool {
+	return r.Spec != ""
+}
+
+// onceSchedule odpala zadanie crona dokładnie raz, o wskazanym czasie.
+type onceSchedule struct {
+	at time.Time
+}
+
+func (o onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(o.at) {
+		return o.at
+	}
+	return time.Time{}
+}
+
+const (
+	defaultReminderHour = 9
+	// maxReminderYears ogranicza termin jednorazowego przypomnienia; chroni też przed przepełnieniem time.Duration.
+	maxReminderYears = 5
+	maxReminderDelay = maxReminderYears * 366 * 24 * time.Hour
+	// nieudaną wysyłkę jednorazowego przypomnienia ponawiamy kilka razy, potem czeka na restart
+	reminderRetries    = 3
+	reminderRetryDelay = 5 * time.Minute
+)
+
+var weekdayNames = map[string]time.Weekday{
+	"poniedziałek": time.Monday,
+	"poniedzialek": time.Monday,
+	"wtorek":       time.Tuesday,
+	"środa":        time.Wednesday,
+	"środę":        time.Wednesday,
+	"sroda":        time.Wednesday,
+	"srode":        time.Wednesday,
+	"czwartek":     time.Thursday,
+	"piątek":       time.Friday,
+	"piatek":       time.Friday,
+	"sobota":       time.Saturday,
+	"sobotę":       time.Saturday,
+	"sobote":       time.Saturday,
+	"niedziela":    time.Sunday,
+	"niedzielę":    time.Sunday,
+	"niedziele":    time.Sunday,
+}
+
+var durationUnits = map[string]time.Duration{
+	"m":        time.Minute,
+	"min":      time.Minute,
+	"minuta":   time.Minute,
+	"minuty":   time.Minute,
+	"minut":    time.Minute,
+	"minutę":   time.Minute,
+	"h":        time.Hour,
+	"g":        time.Hour,
+	"godz":     time.Hour,
+	"godzina":  time.Hour,
+	"godzinę":  time.Hour,
+	"godziny":  time.Hour,
+	"godzin":   time.Hour,
+	"d":        24 * time.Hour,
+	"dzień":    24 * time.Hour,
+	"dzien":    24 * time.Hour,
+	"dni":      24 * time.Hour,
+	"t":        7 * 24 * time.Hour,
+	"tydzień":  7 * 24 * time.Hour,
+	"tydzien":  7 * 24 * time.Hour,
+	"tygodnie": 7 * 24 * time.Hour,
+	"tygodni":  7 * 24 * time.Hour,
+}
+
+var (
+	compactDurationRe = regexp.MustCompile(`^(\d+)([a-ząćęłńóśźż]+)$`)
+	clockRe           = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?$`)
+	dottedDateRe      = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})(?:\.(\d{4}))?$`)
+	digitsRe          = regexp.MustCompile(`^\d+$`)
+)
+
+// parseReminder rozpoznaje polskie wyrażenia czasu na początku tekstu,
+// np. "2h", "za 2 godziny", "jutro 9:00", "2026-11-01 10:00", "co piątek 16:00".
+// Zwraca przypomnienie z wypełnionym At albo Spec oraz pozostały tekst.
+func parseReminder(input string, now time.Time) (Reminder, error) {
+	fields := strings.Fields(input)
+	r := Reminder{}
+	if len(fields) > 0 && strings.ToLower(fields[0]) == "dm" {
+		r.DM = true
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return r, trError("reminder.err.no_time")
+	}
+
+	lower := make([]string, len(fields))
+	for i, f := range fields {
+		lower[i] = strings.ToLower(f)
+	}
+
+	var used int
+	var err error
+	switch {
+	case lower[0] == "co" || lower[0] == "codziennie":
+		used, err = parseRecurring(&r, lower)
+	default:
+		used, err = parseOnce(&r, lower, now)
+	}
+	if err != nil {
+		return r, err
+	}
+
+	r.Text = strings.TrimSpace(strings.Join(fields[used:], " "))
+	if r.Text == "" {
+		return r, trError("reminder.err.no_text")
+	}
+	return r, nil
+}
+
+func parseRecurring(r *Reminder, f []string) (int, error) {
+	i := 1
+	dow := "*"
+	label := "codziennie"
+	if f[0] == "co" {
+		if len(f) < 2 {
+			return 0, trError("reminder.err.no_day")
+		}
+		switch f[1] {
+		case "dzień", "dzien", "dziennie":
+		default:
+			wd, ok := weekdayNames[f[1]]
+			if !ok {
+				return 0, trError("reminder.err.unknown", "co "+f[1])
+			}
+			dow = strconv.Itoa(int(wd))
+			label = "co " + tr(defaultLanguage, fmt.Sprintf("weekday.%d", wd))
+		}
+		i = 2
+	}
+
+	hour, minute := defaultReminderHour, 0
+	if i < len(f) && strings.Contains(f[i], ":") {
+		if h, m, ok := parseClock(f[i]); ok {
+			hour, minute = h, m
+			i++
+		}
+	}
+	r.Spec = fmt.Sprintf("%d %d * * %s", minute, hour, dow)
+	r.Label = fmt.Sprintf("%s %02d:%02d", label, hour, minute)
+	return i, nil
+}
+
+func parseOnce(r *Reminder, f []string, now time.Time) (int, error) {
+	i := 0
+	if f[0] == "za" || f[0] == "w" || f[0] == "we" {
+		i = 1
+	}
+	if i >= len(f) {
+		return 0, trError("reminder.err.no_time")
+	}
+
+	// "2h", "30min", "2h30m"
+	if d, ok, err := parseCompactDuration(f[i]); err != nil {
+		return 0, err
+	} else if ok {
+		r.At = now.Add(d)
+		return i + 1, nil
+	}
+	// "2 godziny"
+	if digitsRe.MatchString(f[i]) && i+1 < len(f) {
+		if unit, ok := durationUnits[f[i+1]]; ok {
+			d, err := scaleDuration(f[i], unit)
+			if err != nil {
+				return 0, err
+			}
+			r.At = now.Add(d)
+			return i + 2, nil
+		}
+	}
+
+	var day time.Time
+	switch f[i] {
+	case "dziś", "dzis", "dzisiaj":
+		day = now
+	case "jutro":
+		day = now.AddDate(0, 0, 1)
+	case "pojutrze":
+		day = now.AddDate(0, 0, 2)
+	default:
+		if d, err := time.ParseInLocation("2006-01-02", f[i], now.Location()); err == nil {
+			day = d
+		} else if m := dottedDateRe.FindStringSubmatch(f[i]); m != nil {
+			d, ok := dottedDate(m, now)
+			if !ok {
+				return 0, trError("reminder.err.bad_date", f[i])
+			}
+			day = d
+		} else if wd, ok := weekdayNames[f[i]]; ok {
+			offset := (int(wd) - int(now.Weekday()) + 7) % 7
+			if offset == 0 {
+				offset = 7
+			}
+			day = now.AddDate(0, 0, offset)
+		}
+	}
+
+	if day.IsZero() {
+		// samo "18:00" albo "o 18:00" - dziś, a jeśli już minęło, to jutro
+		j := i
+		if f[j] == "o" && j+1 < len(f) {
+			j++
+		}
+		h, m, ok := parseClock(f[j])
+		if !ok || !strings.Contains(f[j], ":") {
+			return 0, trError("reminder.err.bad_time", f[i])
+		}
+		at := time.Date(now.Year(), now.Month(), now.Day(), h, m, 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		r.At = at
+		return j + 1, nil
+	}
+
+	i++
+	hour, minute := defaultReminderHour, 0
+	explicit := false
+	if i < len(f) && f[i] == "o" && i+1 < len(f) {
+		i++
+		explicit = true
+	}
+	if i < len(f) && (explicit || strings.Contains(f[i], ":")) {
+		h, m, ok := parseClock(f[i])
+		if !ok && explicit {
+			return 0, trError("reminder.err.bad_time", f[i])
+		}
+		if ok {
+			hour, minute = h, m
+			i++
+		}
+	}
+	r.At = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location())
+	if !r.At.After(now) {
+		return 0, trError("reminder.err.past")
+	}
+	if r.At.After(now.AddDate(maxReminderYears, 0, 0)) {
+		return 0, trError("reminder.err.too_far", maxReminderYears)
+	}
+	return i, nil
+}
+
+// parseCompactDuration czyta "2h" albo "2h30m"; ok mówi, czy tekst w ogóle jest czasem trwania,
+// a błąd oznacza zbyt odległy termin.
+func parseCompactDuration(s string) (time.Duration, bool, error) {
+	if m := compactDurationRe.FindStringSubmatch(s); m != nil {
+		if unit, ok := durationUnits[m[2]]; ok {
+			d, err := scaleDuration(m[1], unit)
+			return d, d > 0, err
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		if d > maxReminderDelay {
+			return 0, false, trError("reminder.err.too_far", maxReminderYears)
+		}
+		return d, true, nil
+	}
+	return 0, false, nil
+}
+
+// scaleDuration mnoży liczbę z tekstu przez jednostkę, sprawdzając limit przed mnożeniem.
+func scaleDuration(digits string, unit time.Duration) (time.Duration, error) {
+	n, err := strconv.Atoi(digits)
+	if err != nil || n > int(maxReminderDelay/unit) {
+		return 0, trError("reminder.err.too_far", maxReminderYears)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func parseClock(s string) (int, int, bool) {
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+// dottedDate zamienia "31.12" albo "31.12.2026" na datę; bez roku wybiera najbliższy
+// przyszły termin. Zwraca false dla dat, których nie ma w kalendarzu, np. 31.02.
+func dottedDate(m []string, now time.Time) (time.Time, bool) {
+	day, _ := strconv.Atoi(m[1])
+	month, _ := strconv.Atoi(m[2])
+	year := now.Year()
+	if m[3] != "" {
+		year, _ = strconv.Atoi(m[3])
+	}
+	d, ok := calendarDate(year, month, day, now.Location())
+	if m[3] != "" {
+		return d, ok
+	}
+	// 29.02 poza rokiem przestępnym szukamy w kolejnych latach
+	for y := year; (!ok || d.Before(now)) && y <= year+maxReminderYears; {
+		y++
+		d, ok = calendarDate(y, month, day, now.Location())
+	}
+	return d, ok
+}
+
+func calendarDate(year, month, day int, loc *time.Location) (time.Time, bool) {
+	d := time.Date(year, time.Month(month), day, 23, 59, 0, 0, loc)
+	return d, d.Day() == day && d.Month() == time.Month(month)
+}
+
+func (sch *scheduler) scheduleReminder(r Reminder) error {
+	job := cron.FuncJob(func() { sch.fireReminder(r.ID) })
+
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+
+	var id cron.EntryID
+	if r.recurring() {
+		var err error
+		id, err = sch.cron.AddJob(JobConfig{Spec: r.Spec}.cronSpec(), job)
+		if err != nil {
+			return err
+		}
+	} else {
+		if !r.At.After(time.Now()) {
+			return nil
+		}
+		id = sch.cron.Schedule(onceSchedule{at: r.At}, job)
+	}
+	sch.reminders[r.ID] = id
+	return nil
+}
+
+func (sch *scheduler) unscheduleReminder(id int) {
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+	if entryID, ok := sch.reminders[id]; ok {
+		sch.cron.Remove(entryID)
+		delete(sch.reminders, id)
+	}
+	delete(sch.reminderFailures, id)
+}
+
+func (sch *scheduler) fireReminder(id int) {
+	r, ok := findReminder(id)
+	if !ok {
+		return
+	}
+	if err := deliverReminder(sch.session, r); err != nil {
+		slog.Error("błąd wysyłki przypomnienia", "reminder", r.ID, "user", r.UserID, "channel", r.ChannelID, "error", err)
+		if !r.recurring() {
+			sch.retryReminder(r)
+		}
+		return
+	}
+	if !r.recurring() {
+		sch.unscheduleReminder(id)
+		removeReminder(id)
+	}
+}
+
+// retryReminder planuje ponowną wysyłkę jednorazowego przypomnienia. Po wyczerpaniu
+// prób przypomnienie zostaje w konfiguracji i wyjdzie przy następnym starcie.
+func (sch *scheduler) retryReminder(r Reminder) {
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+	if entryID, ok := sch.reminders[r.ID]; ok {
+		sch.cron.Remove(entryID)
+		delete(sch.reminders, r.ID)
+	}
+	sch.reminderFailures[r.ID]++
+	if sch.reminderFailures[r.ID] > reminderRetries {
+		slog.Warn("rezygnuję z ponowień przypomnienia do restartu", "reminder", r.ID, "attempts", sch.reminderFailures[r.ID])
+		return
+	}
+	id := r.ID
+	at := time.Now().Add(reminderRetryDelay)
+	sch.reminders[r.ID] = sch.cron.Schedule(onceSchedule{at: at}, cron.FuncJob(func() { sch.fireReminder(id) }))
+}
+
+// deliverOverdueReminders wysyła jednorazowe przypomnienia, których termin minął podczas przestoju.
+func (sch *scheduler) deliverOverdueReminders(now time.Time) {
+	for _, r := range listReminders("") {
+		if r.recurring() || r.At.After(now) {
+			continue
+		}
+		sch.fireReminder(r.ID)
+	}
+}
+
+func deliverReminder(s *discordgo.Session, r Reminder) error {
+	msg := tr(channelLang(s, r.ChannelID), "reminder.fired", r.UserID, r.Text)
+	channelID := r.ChannelID
+	if r.DM {
+		ch, err := s.UserChannelCreate(r.UserID)
+		if err != nil {
+			return err
+		}
+		channelID = ch.ID
+	}
+	_, err := s.ChannelMessageSend(channelID, msg)
+	return err
+}
+
+func addReminder(r Reminder) Reminder {
+	configMu.Lock()
+	config.NextReminderID++
+	r.ID = config.NextReminderID
+	config.Reminders = append(config.Reminders, r)
+	configMu.Unlock()
+	saveConfig()
+	return r
+}
+
+func findReminder(id int) (Reminder, bool) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	for _, r := range config.Reminders {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Reminder{}, false
+}
+
+func removeReminder(id int) bool {
+	configMu.Lock()
+	removed := false
+	kept := config.Reminders[:0]
+	for _, r := range config.Reminders {
+		if r.ID == id {
+			removed = true
+			continue
+		}
+		kept = append(kept, r)
+	}
+	config.Reminders = kept
+	configMu.Unlock()
+	if removed {
+		saveConfig()
+	}
+	return removed
+}
+
+func listReminders(userID string) []Reminder {
+	configMu.Lock()
+	defer configMu.Unlock()
+	out := make([]Reminder, 0, len(config.Reminders))
+	for _, r := range config.Reminders {
+		if userID == "" || r.UserID == userID {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func handleRemindCommand(s *discordgo.Session, m *discordgo.MessageCreate, args string) {
+	lang := langFor(m.GuildID)
+	loc, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		loc = time.Local
+	}
+	r, err := parseReminder(args, time.Now().In(loc))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, "❌ "+localizeError(lang, err)+"\n"+tr(lang, "reminder.examples"))
+		return
+	}
+	r.UserID = m.Author.ID
+	r.ChannelID = m.ChannelID
+	r.Created = time.Now()
+	r = addReminder(r)
+
+	if jobScheduler != nil {
+		if err := jobScheduler.scheduleReminder(r); err != nil {
+			removeReminder(r.ID)
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "reminder.schedule_failed"))
+			return
+		}
+	}
+	s.ChannelMessageSend(m.ChannelID, tr(lang, "reminder.added", r.ID, describeReminderTime(lang, r), r.Text))
+}
+
+func handleRemindersCommand(s *discordgo.Session, m *discordgo.MessageCreate, args string) {
+	lang := langFor(m.GuildID)
+	fields := strings.Fields(args)
+	if len(fields) == 2 && (fields[0] == "usun" || fields[0] == "anuluj") {
+		id, err := strconv.Atoi(strings.TrimPrefix(fields[1], "#"))
+		r, ok := findReminder(id)
+		if err != nil || !ok || r.UserID != m.Author.ID {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "reminder.not_found"))
+			return
+		}
+		if jobScheduler != nil {
+			jobScheduler.unscheduleReminder(id)
+		}
+		removeReminder(id)
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "reminder.cancelled", id))
+		return
+	}
+
+	reminders := listReminders(m.Author.ID)
+	if len(reminders) == 0 {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "reminder.none"))
+		return
+	}
+	sort.Slice(reminders, func(i, j int) bool { return reminders[i].ID < reminders[j].ID })
+
+	var b strings.Builder
+	b.WriteString(trn(lang, "reminder.list_header", len(reminders), len(reminders)))
+	for _, r := range reminders {
+		b.WriteString(fmt.Sprintf("\n#%d — %s — %s", r.ID, describeReminderTime(lang, r), r.Text))
+	}
+	b.WriteString("\n\n" + tr(lang, "reminder.cancel_hint"))
+	s.ChannelMessageSend(m.ChannelID, b.String())
+}
+
+// describeReminderTime opisuje termin przypomnienia. Dla cyklicznych odtwarza opis
+// z harmonogramu, żeby był w języku odbiorcy; Label zostaje jako zapas.
+func describeReminderTime(lang string, r Reminder) string {
+	if r.recurring() {
+		var minute, hour int
+		var dow string
+		if _, err := fmt.Sscanf(r.Spec, "%d %d * * %s", &minute, &hour, &dow); err != nil {
+			return r.Label
+		}
+		if dow == "*" {
+			return tr(lang, "reminder.daily", hour, minute)
+		}
+		wd, err := strconv.Atoi(dow)
+		if err != nil {
+			return r.Label
+		}
+		return tr(lang, "reminder.weekly", tr(lang, fmt.Sprintf("weekday.%d", wd)), hour, minute)
+	}
+	loc, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		loc = time.Local
+	}
+	return r.At.In(loc).Format("2006-01-02 15:04")
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestParseReminder(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// niedziela
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, loc)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		input   string
+		at      time.Time
+		spec    string
+		text    string
+		dm      bool
+		errKey  string
+		errArgs []any
+	}{
+		{input: "2h kawa", at: now.Add(2 * time.Hour), text: "kawa"},
+		{input: "za 30min herbata", at: now.Add(30 * time.Minute), text: "herbata"},
+		{input: "za 2 godziny spotkanie", at: now.Add(2 * time.Hour), text: "spotkanie"},
+		{input: "1h30m piekarnik", at: now.Add(90 * time.Minute), text: "piekarnik"},
+		{input: "dm 3 dni rachunek", at: now.Add(72 * time.Hour), text: "rachunek", dm: true},
+		{input: "jutro zadzwoń", at: at(time.October, 19, 9, 0), text: "zadzwoń"},
+		{input: "jutro o 18:30 trening", at: at(time.October, 19, 18, 30), text: "trening"},
+		{input: "jutro o 7 pobudka", at: at(time.October, 19, 7, 0), text: "pobudka"},
+		{input: "18:00 obiad", at: at(time.October, 18, 18, 0), text: "obiad"},
+		{input: "o 11:00 raport", at: at(time.October, 19, 11, 0), text: "raport"},
+		{input: "piątek 16:00 piwo", at: at(time.October, 23, 16, 0), text: "piwo"},
+		{input: "niedziela msza", at: at(time.October, 25, 9, 0), text: "msza"},
+		{input: "2026-11-01 10:00 znicze", at: at(time.November, 1, 10, 0), text: "znicze"},
+		{input: "24.12 o 20:00 prezenty", at: at(time.December, 24, 20, 0), text: "prezenty"},
+		{input: "1.03 czynsz", at: time.Date(2027, time.March, 1, 9, 0, 0, 0, loc), text: "czynsz"},
+		{input: "29.02 urodziny", at: time.Date(2028, time.February, 29, 9, 0, 0, 0, loc), text: "urodziny"},
+		{input: "co piątek 16:00 weekend", spec: "0 16 * * 5", text: "weekend"},
+		{input: "codziennie leki", spec: "0 9 * * *", text: "leki"},
+
+		{input: "", errKey: "reminder.err.no_time"},
+		{input: "2h", errKey: "reminder.err.no_text"},
+		{input: "co", errKey: "reminder.err.no_day"},
+		{input: "kiedyś coś", errKey: "reminder.err.bad_time", errArgs: []any{"kiedyś"}},
+		{input: "jutro o 25:00 x", errKey: "reminder.err.bad_time", errArgs: []any{"25:00"}},
+		{input: "31.02 x", errKey: "reminder.err.bad_date", errArgs: []any{"31.02"}},
+		{input: "31.04.2027 x", errKey: "reminder.err.bad_date", errArgs: []any{"31.04.2027"}},
+		{input: "29.02.2027 x", errKey: "reminder.err.bad_date", errArgs: []any{"29.02.2027"}},
+		{input: "1.01.2020 x", errKey: "reminder.err.past"},
+		{input: "1.01.2040 x", errKey: "reminder.err.too_far", errArgs: []any{maxReminderYears}},
+		{input: "99999999999999999999d x", errKey: "reminder.err.too_far", errArgs: []any{maxReminderYears}},
+		{input: "za 3000000 godzin x", errKey: "reminder.err.too_far", errArgs: []any{maxReminderYears}},
+		{input: "100000h x", errKey: "reminder.err.too_far", errArgs: []any{maxReminderYears}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			r, err := parseReminder(tt.input, now)
+			if tt.errKey != "" {
+				var le *localizedError
+				if !errors.As(err, &le) || le.Key != tt.errKey {
+					t.Fatalf("err = %v, want %s", err, tt.errKey)
+				}
+				if tt.errArgs != nil && (len(le.Args) != len(tt.errArgs) || le.Args[0] != tt.errArgs[0]) {
+					t.Errorf("args = %v, want %v", le.Args, tt.errArgs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !r.At.Equal(tt.at) {
+				t.Errorf("At = %v, want %v", r.At, tt.at)
+			}
+			if r.Spec != tt.spec {
+				t.Errorf("Spec = %q, want %q", r.Spec, tt.spec)
+			}
+			if r.Text != tt.text {
+				t.Errorf("Text = %q, want %q", r.Text, tt.text)
+			}
+			if r.DM != tt.dm {
+				t.Errorf("DM = %v, want %v", r.DM, tt.dm)
+			}
+		})
+	}
+}
+
+// failingTransport udaje niedostępne API Discorda.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("discord niedostępny")
+}
+
+func TestFireReminderKeepsUndelivered(t *testing.T) {
+	useTempConfig(t)
+	s, discord := newFakeSession(t)
+	s.Client = &http.Client{Transport: failingTransport{}}
+	sch := &scheduler{
+		cron:             cron.New(),
+		session:          s,
+		reminders:        make(map[int]cron.EntryID),
+		reminderFailures: make(map[int]int),
+	}
+	r := addReminder(Reminder{UserID: "u1", ChannelID: "c1", Text: "kawa", At: time.Now().Add(-time.Minute)})
+
+	for attempt := 1; attempt <= reminderRetries+1; attempt++ {
+		sch.deliverOverdueReminders(time.Now())
+		if _, ok := findReminder(r.ID); !ok {
+			t.Fatalf("próba %d: przypomnienie usunięte mimo błędu wysyłki", attempt)
+		}
+		_, retry := sch.reminders[r.ID]
+		if want := attempt <= reminderRetries; retry != want {
+			t.Errorf("próba %d: ponowienie zaplanowane = %v, want %v", attempt, retry, want)
+		}
+	}
+
+	s.Client = &http.Client{Transport: discord}
+	sch.fireReminder(r.ID)
+	if _, ok := findReminder(r.ID); ok {
+		t.Error("przypomnienie zostało po udanej wysyłce")
+	}
+	if len(sch.reminders) != 0 || len(sch.reminderFailures) != 0 {
+		t.Errorf("po wysyłce zostały wpisy: %v, %v", sch.reminders, sch.reminderFailures)
+	}
+	if !strings.Contains(discord.last(), "kawa") {
+		t.Errorf("wysłano %q", discord.last())
+	}
+}
@@ -0,0 +1,651 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	defaultTimezone     = "Europe/Warsaw"
+	defaultRetryBackoff = 30 * time.Second
+	maxRetryBackoff     = 15 * time.Minute
+)
+
+// scheduledJob opisuje zadanie crona; jego opis jest w katalogach tłumaczeń pod kluczem job.<nazwa>.
+type scheduledJob struct {
+	Name    string
+	Default JobConfig
+	// Due zawęża wywołania crona, np. do ostatniego dnia miesiąca.
+	// Nadrabianie pominiętych uruchomień bierze pod uwagę tylko terminy, dla których zwraca true.
+	Due func(t time.Time) bool
+	Run func(ctx context.Context, s *discordgo.Session, now time.Time) error
+	// Failed jest wołane raz, gdy wszystkie próby zawiodą.
+	Failed func(s *discordgo.Session, now time.Time, err error)
+	// Channel zwraca kanał, na który zadanie wysyła wyniki; jego język mają alerty o błędach w DM.
+	Channel func() string
+}
+
+var scheduledJobs = []scheduledJob{
+	{
+		Name: "cytat",
+		Default: JobConfig{
+			Spec:          "0 9 * * ?",
+			Timezone:      defaultTimezone,
+			Enabled:       true,
+			CatchUpWindow: "3h",
+			Retries:       2,
+			RetryBackoff:  "30s",
+		},
+		Run:     runDailyQuoteJob,
+		Channel: func() string { return readConfig(func(c *Config) string { return c.ChannelID }) },
+	},
+	{
+		Name: "gem",
+		Default: JobConfig{
+			Spec:          "0 10 * * *",
+			Timezone:      defaultTimezone,
+			Enabled:       true,
+			CatchUpWindow: "36h",
+			Retries:       3,
+			RetryBackoff:  "1m",
+		},
+		Due:     isLastDayOfMonth,
+		Run:     runMonthlyGemJob,
+		Failed:  gemJobFailed,
+		Channel: func() string { return readConfig(func(c *Config) string { return c.GemChannelID }) },
+	},
+	{
+		Name: "pogoda",
+		Default: JobConfig{
+			Spec:          "0 19 * * *",
+			Timezone:      defaultTimezone,
+			Enabled:       true,
+			CatchUpWindow: "3h",
+			Retries:       2,
+			RetryBackoff:  "30s",
+		},
+		Run: runWeatherJob,
+	},
+	{
+		Name: "ostrzezenia",
+		Default: JobConfig{
+			Spec:         "0 */3 * * *",
+			Timezone:     defaultTimezone,
+			Enabled:      true,
+			Retries:      1,
+			RetryBackoff: "5m",
+		},
+		Run: checkWeatherAlerts,
+	},
+	{
+		// co kwadrans w dni robocze; poza sesją giełdy danego tickera alert nie jest sprawdzany
+		Name: "alerty",
+		Default: JobConfig{
+			Spec:         "*/15 * * * 1-5",
+			Timezone:     defaultTimezone,
+			Enabled:      true,
+			Retries:      1,
+			RetryBackoff: "1m",
+		},
+		Run: checkPriceAlerts,
+	},
+}
+
+func findScheduledJob(name string) (scheduledJob, bool) {
+	for _, job := range scheduledJobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return scheduledJob{}, false
+}
+
+func jobConfig(name string) JobConfig {
+	configMu.Lock()
+	jc, ok := config.Jobs[name]
+	configMu.Unlock()
+	if ok {
+		return jc
+	}
+	if job, ok := findScheduledJob(name); ok {
+		return job.Default
+	}
+	return JobConfig{}
+}
+
+func (jc JobConfig) location() (*time.Location, error) {
+	tz := jc.Timezone
+	if tz == "" {
+		tz = defaultTimezone
+	}
+	return time.LoadLocation(tz)
+}
+
+// cronSpec dokleja strefę czasową w formacie rozumianym przez parser robfig/cron.
+func (jc JobConfig) cronSpec() string {
+	tz := jc.Timezone
+	if tz == "" {
+		tz = defaultTimezone
+	}
+	return "CRON_TZ=" + tz + " " + jc.Spec
+}
+
+func (jc JobConfig) validate() error {
+	if _, err := jc.location(); err != nil {
+		return trError("schedule.err.timezone", jc.Timezone)
+	}
+	if _, err := cron.ParseStandard(jc.cronSpec()); err != nil {
+		return trError("schedule.err.spec", jc.Spec, err)
+	}
+	if _, err := jc.catchUpWindow(); err != nil {
+		return trError("schedule.err.catch_up", jc.CatchUpWindow)
+	}
+	if _, err := jc.retryBackoff(); err != nil {
+		return trError("schedule.err.backoff", jc.RetryBackoff)
+	}
+	if jc.Retries < 0 {
+		return trError("schedule.err.retries")
+	}
+	return nil
+}
+
+func (jc JobConfig) retryBackoff() (time.Duration, error) {
+	if jc.RetryBackoff == "" {
+		return defaultRetryBackoff, nil
+	}
+	return time.ParseDuration(jc.RetryBackoff)
+}
+
+func (jc JobConfig) catchUpWindow() (time.Duration, error) {
+	if jc.CatchUpWindow == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(jc.CatchUpWindow)
+}
+
+type scheduler struct {
+	mu   sync.Mutex
+	cron *cron.Cron
+	// ctx jest przekazywany zadaniom; anulowany dopiero po przekroczeniu limitu czasu zamykania.
+	ctx     context.Context
+	session *discordgo.Session
+	entries map[string]cron.EntryID
+	running map[string]bool
+	// reminders mapuje ID przypomnienia na wpis w cronie
+	reminders map[int]cron.EntryID
+	// reminderFailures liczy nieudane wysyłki jednorazowych przypomnień
+	reminderFailures map[int]int
+	started          bool
+	// quit jest zamykany przy zatrzymaniu; przerywa oczekiwanie między ponowieniami.
+	quit chan struct{}
+	// jobs liczy trwające uruchomienia, także te nadrabiane poza cronem.
+	jobs sync.WaitGroup
+}
+
+var jobScheduler *scheduler
+
+func startCronScheduler(ctx context.Context, s *discordgo.Session) {
+	loc, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		fatal("nieznana strefa czasowa", "timezone", defaultTimezone, "error", err)
+	}
+
+	sch := &scheduler{
+		cron:             cron.New(cron.WithLocation(loc)),
+		ctx:              ctx,
+		session:          s,
+		entries:          make(map[string]cron.EntryID, len(scheduledJobs)),
+		running:          make(map[string]bool, len(scheduledJobs)),
+		reminders:        make(map[int]cron.EntryID),
+		reminderFailures: make(map[int]int),
+		quit:             make(chan struct{}),
+	}
+	for _, job := range scheduledJobs {
+		if err := sch.register(job, jobConfig(job.Name)); err != nil {
+			fatal("błąd rejestracji zadania", "job", job.Name, "error", err)
+		}
+	}
+	for _, r := range listReminders("") {
+		if err := sch.scheduleReminder(r); err != nil {
+			slog.Error("błąd planowania przypomnienia", "reminder", r.ID, "error", err)
+		}
+	}
+
+	jobScheduler = sch
+	slog.Info("cron działa", "jobs", len(sch.entries), "reminders", len(sch.reminders))
+	sch.mu.Lock()
+	sch.started = true
+	sch.mu.Unlock()
+	sch.cron.Start()
+}
+
+func (sch *scheduler) isRunning() bool {
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+	return sch.started
+}
+
+// stop zatrzymuje crona i nowe uruchomienia zadań. Zwrócony kanał zamyka się,
+// gdy skończą się wszystkie trwające zadania i przypomnienia.
+func (sch *scheduler) stop() <-chan struct{} {
+	sch.mu.Lock()
+	if sch.started {
+		sch.started = false
+		close(sch.quit)
+	}
+	sch.mu.Unlock()
+
+	cronCtx := sch.cron.Stop()
+	done := make(chan struct{})
+	go func() {
+		<-cronCtx.Done()
+		sch.jobs.Wait()
+		close(done)
+	}()
+	return done
+}
+
+func (sch *scheduler) register(job scheduledJob, jc JobConfig) error {
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+
+	if id, ok := sch.entries[job.Name]; ok {
+		sch.cron.Remove(id)
+		delete(sch.entries, job.Name)
+	}
+	if !jc.Enabled {
+		return nil
+	}
+
+	if err := jc.validate(); err != nil {
+		return err
+	}
+	loc, _ := jc.location()
+	id, err := sch.cron.AddFunc(jc.cronSpec(), func() {
+		slog.Info("start zadania", "job", job.Name)
+		sch.runJob(job, time.Now().In(loc).Truncate(time.Minute))
+	})
+	if err != nil {
+		return err
+	}
+	sch.entries[job.Name] = id
+	return nil
+}
+
+type jobAttempt struct {
+	Started  time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// jobReport opisuje przebieg jednego uruchomienia zadania wraz ze wszystkimi próbami.
+type jobReport struct {
+	Job       string
+	Scheduled time.Time
+	Attempts  []jobAttempt
+}
+
+func (r jobReport) failed() bool {
+	return len(r.Attempts) > 0 && r.Attempts[len(r.Attempts)-1].Err != nil
+}
+
+func (r jobReport) duration() time.Duration {
+	var d time.Duration
+	for _, a := range r.Attempts {
+		d += a.Duration
+	}
+	return d
+}
+
+func (r jobReport) lastErr() error {
+	if len(r.Attempts) == 0 {
+		return nil
+	}
+	return r.Attempts[len(r.Attempts)-1].Err
+}
+
+func (r jobReport) format(lang string) string {
+	var b strings.Builder
+	b.WriteString(tr(lang, "job.failed_title", r.Job))
+	b.WriteString(tr(lang, "job.failed_scheduled", r.Scheduled.Format("2006-01-02 15:04 MST")))
+	b.WriteString(trn(lang, "job.failed_attempts", len(r.Attempts), len(r.Attempts)))
+	for i, a := range r.Attempts {
+		status := tr(lang, "job.attempt_ok")
+		if a.Err != nil {
+			status = localizeError(lang, a.Err)
+		}
+		b.WriteString(fmt.Sprintf("%d. %s (%s): %s\n", i+1, a.Started.Format("15:04:05"), a.Duration.Round(time.Millisecond), status))
+	}
+	return b.String()
+}
+
+func (sch *scheduler) runJob(job scheduledJob, at time.Time) {
+	if job.Due != nil && !job.Due(at) {
+		return
+	}
+
+	sch.mu.Lock()
+	if !sch.started {
+		sch.mu.Unlock()
+		slog.Warn("bot się zamyka, pomijam uruchomienie", "job", job.Name, "scheduled", at)
+		countJobRun(job.Name, "skipped")
+		return
+	}
+	if sch.running[job.Name] {
+		sch.mu.Unlock()
+		slog.Warn("zadanie już trwa, pomijam uruchomienie", "job", job.Name, "scheduled", at)
+		countJobRun(job.Name, "skipped")
+		return
+	}
+	sch.running[job.Name] = true
+	sch.jobs.Add(1)
+	sch.mu.Unlock()
+	defer func() {
+		sch.mu.Lock()
+		delete(sch.running, job.Name)
+		sch.mu.Unlock()
+		sch.jobs.Done()
+	}()
+
+	report := sch.runWithRetries(job, jobConfig(job.Name), at)
+	if report.failed() {
+		slog.Error("zadanie nie powiodło się", "job", job.Name, "attempts", len(report.Attempts), "duration", report.duration(), "error", report.lastErr())
+		countJobRun(job.Name, "failure")
+		recordError("job")
+		if job.Failed != nil {
+			job.Failed(sch.session, at, report.lastErr())
+		}
+		notifyJobFailure(sch.session, report)
+		return
+	}
+	countJobRun(job.Name, "success")
+	slog.Info("zadanie zakończone", "job", job.Name, "attempts", len(report.Attempts), "duration", report.duration())
+	recordJobRun(job.Name, at)
+}
+
+// runWithRetries ponawia zadanie z rosnącym odstępem. Przy zamykaniu bota
+// nie czeka na kolejną próbę, tylko zwraca raport z ostatnim błędem.
+func (sch *scheduler) runWithRetries(job scheduledJob, jc JobConfig, at time.Time) jobReport {
+	report := jobReport{Job: job.Name, Scheduled: at}
+	backoff, err := jc.retryBackoff()
+	if err != nil {
+		backoff = defaultRetryBackoff
+	}
+
+	for attempt := 0; attempt <= jc.Retries; attempt++ {
+		if attempt > 0 {
+			slog.Warn("ponowienie zadania", "job", job.Name, "attempt", attempt, "retries", jc.Retries, "backoff", backoff)
+			select {
+			case <-time.After(backoff):
+			case <-sch.quit:
+				slog.Warn("bot się zamyka, rezygnuję z ponowień", "job", job.Name, "attempt", attempt)
+				return report
+			case <-sch.ctx.Done():
+				return report
+			}
+			backoff *= 2
+			if backoff > maxRetryBackoff {
+				backoff = maxRetryBackoff
+			}
+		}
+		started := time.Now()
+		err := job.Run(sch.ctx, sch.session, at)
+		duration := time.Since(started)
+		observeJobAttempt(job.Name, duration)
+		report.Attempts = append(report.Attempts, jobAttempt{
+			Started:  started,
+			Duration: duration,
+			Err:      err,
+		})
+		if err == nil {
+			break
+		}
+	}
+	return report
+}
+
+func notifyJobFailure(s *discordgo.Session, report jobReport) {
+	configMu.Lock()
+	channelID, userID, guildID := config.AlertChannelID, config.AlertUserID, config.AlertGuildID
+	configMu.Unlock()
+	if channelID != "" {
+		msg := report.format(channelLang(s, channelID))
+		if _, err := s.ChannelMessageSend(channelID, msg); err != nil {
+			slog.Error("błąd wysyłania alertu", "job", report.Job, "channel", channelID, "error", err)
+		}
+	}
+	if userID != "" {
+		ch, err := s.UserChannelCreate(userID)
+		if err != nil {
+			slog.Error("błąd otwierania DM", "job", report.Job, "user", userID, "error", err)
+			return
+		}
+		if _, err := s.ChannelMessageSend(ch.ID, report.format(jobLang(s, report.Job, guildID))); err != nil {
+			slog.Error("błąd wysyłania alertu DM", "job", report.Job, "user", userID, "error", err)
+		}
+	}
+}
+
+// jobLang zwraca język kanału, do którego pisze zadanie, a dla zadań bez jednego kanału
+// (np. subskrypcje pogody) język serwera, z którego włączono alerty.
+func jobLang(s *discordgo.Session, name, guildID string) string {
+	if job, ok := findScheduledJob(name); ok && job.Channel != nil {
+		if channelID := job.Channel(); channelID != "" {
+			return channelLang(s, channelID)
+		}
+	}
+	return langFor(guildID)
+}
+
+func handleFailureAlertsCommand(s *discordgo.Session, m *discordgo.MessageCreate, arg string) {
+	lang := langFor(m.GuildID)
+	switch strings.TrimSpace(arg) {
+	case "kanal":
+		updateConfig(func(c *Config) { c.AlertChannelID = m.ChannelID })
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "failures.channel"))
+	case "dm":
+		updateConfig(func(c *Config) {
+			c.AlertUserID = m.Author.ID
+			c.AlertGuildID = m.GuildID
+		})
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "failures.dm"))
+	case "off":
+		updateConfig(func(c *Config) {
+			c.AlertChannelID = ""
+			c.AlertUserID = ""
+			c.AlertGuildID = ""
+		})
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "failures.off"))
+	default:
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "failures.usage"))
+	}
+}
+
+// catchUpMissedRuns uruchamia raz zadania, których termin minął podczas
+// przestoju bota, o ile zmieścił się w oknie nadrabiania danego zadania.
+func (sch *scheduler) catchUpMissedRuns(now time.Time) {
+	for _, job := range scheduledJobs {
+		jc := jobConfig(job.Name)
+		if !jc.Enabled {
+			continue
+		}
+		last := lastJobRun(job.Name)
+		if last.IsZero() {
+			// brak zapisu to pierwszy start z nadrabianiem; poprzednia wersja mogła już wysłać
+			recordJobRun(job.Name, now)
+			continue
+		}
+		at, ok := missedRun(job, jc, last, now)
+		if !ok {
+			continue
+		}
+		slog.Info("nadrabiam zadanie", "job", job.Name, "scheduled", at)
+		sch.runJob(job, at)
+	}
+	sch.deliverOverdueReminders(now)
+}
+
+func missedRun(job scheduledJob, jc JobConfig, last, now time.Time) (time.Time, bool) {
+	window, err := jc.catchUpWindow()
+	if err != nil || window <= 0 {
+		return time.Time{}, false
+	}
+	schedule, err := cron.ParseStandard(jc.cronSpec())
+	if err != nil {
+		return time.Time{}, false
+	}
+	// Due sprawdzamy w strefie zadania, jak przy uruchomieniu z crona
+	loc, err := jc.location()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	from := now.Add(-window)
+	if last.After(from) {
+		from = last
+	}
+
+	var missed time.Time
+	for t := schedule.Next(from); !t.After(now); t = schedule.Next(t) {
+		if t = t.In(loc); job.Due == nil || job.Due(t) {
+			missed = t
+		}
+	}
+	return missed, !missed.IsZero()
+}
+
+func (sch *scheduler) nextRun(name string) (time.Time, bool) {
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+
+	id, ok := sch.entries[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	entry := sch.cron.Entry(id)
+	if !entry.Valid() {
+		return time.Time{}, false
+	}
+	return entry.Next, true
+}
+
+// setJobSpec zmienia harmonogram zadania w trakcie działania bota.
+func setJobSpec(name, spec string) error {
+	job, ok := findScheduledJob(name)
+	if !ok {
+		return trError("schedule.err.unknown_job", name)
+	}
+	jc := jobConfig(name)
+	jc.Spec = spec
+	if err := jc.validate(); err != nil {
+		return err
+	}
+	if jobScheduler != nil {
+		if err := jobScheduler.register(job, jc); err != nil {
+			return err
+		}
+	}
+	updateConfig(func(c *Config) {
+		if c.Jobs == nil {
+			c.Jobs = make(map[string]JobConfig)
+		}
+		c.Jobs[name] = jc
+	})
+	return nil
+}
+
+func handleScheduleCommand(s *discordgo.Session, m *discordgo.MessageCreate, args string) {
+	lang := langFor(m.GuildID)
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		s.ChannelMessageSend(m.ChannelID, buildScheduleList(lang))
+		return
+	}
+	if fields[0] != "set" || len(fields) < 3 {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "schedule.usage"))
+		return
+	}
+	name := fields[1]
+	spec := strings.Join(fields[2:], " ")
+	if err := setJobSpec(name, spec); err != nil {
+		s.ChannelMessageSend(m.ChannelID, "❌ "+localizeError(lang, err))
+		return
+	}
+	msg := tr(lang, "schedule.updated", name, spec)
+	if jobScheduler != nil {
+		if next, ok := jobScheduler.nextRun(name); ok {
+			msg += tr(lang, "schedule.next_run", next.Format("2006-01-02 15:04 MST"))
+		}
+	}
+	s.ChannelMessageSend(m.ChannelID, msg)
+}
+
+func buildScheduleList(lang string) string {
+	names := make([]string, 0, len(scheduledJobs))
+	for _, job := range scheduledJobs {
+		names = append(names, job.Name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(tr(lang, "schedule.header"))
+	for _, name := range names {
+		jc := jobConfig(name)
+		next := tr(lang, "schedule.disabled")
+		if jc.Enabled {
+			next = "—"
+			if jobScheduler != nil {
+				if t, ok := jobScheduler.nextRun(name); ok {
+					next = t.Format("2006-01-02 15:04 MST")
+				}
+			}
+		}
+		tz := jc.Timezone
+		if tz == "" {
+			tz = defaultTimezone
+		}
+		b.WriteString(tr(lang, "schedule.entry", name, tr(lang, "job."+name), jc.Spec, tz, next))
+	}
+	return b.String()
+}
+
+func runDailyQuoteJob(ctx context.Context, s *discordgo.Session, now time.Time) error {
+	channelID := readConfig(func(c *Config) string { return c.ChannelID })
+	if channelID == "" {
+		return nil
+	}
+	return sendDailyQuote(s, channelID)
+}
+
+func runMonthlyGemJob(ctx context.Context, s *discordgo.Session, now time.Time) error {
+	channelID := readConfig(func(c *Config) string { return c.GemChannelID })
+	mentions := mentionGemSubscribers()
+	if channelID == "" || mentions == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, gemGenerateTimeout)
+	defer cancel()
+	guildID := channelGuild(s, channelID)
+	opts := defaultChartOptions
+	opts.Theme = chartThemeFor(guildID)
+	return generateAndSendGem(ctx, s, channelID, mentions, langFor(guildID), opts)
+}
+
+func gemJobFailed(s *discordgo.Session, now time.Time, err error) {
+	if channelID := readConfig(func(c *Config) string { return c.GemChannelID }); channelID != "" {
+		lang := channelLang(s, channelID)
+		s.ChannelMessageSend(channelID, tr(lang, "gem.failed", describeChartError(lang, err)))
+	}
+}
+
+func runWeatherJob(ctx context.Context, s *discordgo.Session, now time.Time) error {
+	return sendWeatherSubscriptions(ctx, s)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestNotifyJobFailureLanguage(t *testing.T) {
+	useTempConfig(t)
+	s, discord := newFakeSession(t)
+	s.State.GuildAdd(&discordgo.Guild{ID: "g-en"})
+	s.State.ChannelAdd(&discordgo.Channel{ID: "quotes", GuildID: "g-en"})
+	config.GuildLanguages = map[string]string{"g-en": "en", "g-pl": "pl"}
+	config.ChannelID = "quotes"
+	config.AlertUserID = "admin"
+	config.AlertGuildID = "g-pl"
+
+	report := jobReport{
+		Scheduled: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+		Attempts:  []jobAttempt{{Err: errors.New("timeout")}},
+	}
+	tests := []struct {
+		job  string
+		lang string
+	}{
+		// cytat pisze na kanał serwera anglojęzycznego
+		{"cytat", "en"},
+		// pogoda nie ma jednego kanału: język serwera, z którego włączono alerty
+		{"pogoda", "pl"},
+	}
+	for _, tt := range tests {
+		report.Job = tt.job
+		notifyJobFailure(s, report)
+		if got, want := discord.last(), report.format(tt.lang); got != want {
+			t.Errorf("%s: DM %q, want %q", tt.job, got, want)
+		}
+	}
+}
+
+func TestMissedRun(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	gem := scheduledJob{Name: "gem", Due: isLastDayOfMonth}
+	gemConfig := JobConfig{Spec: "0 10 * * *", Timezone: defaultTimezone, Enabled: true, CatchUpWindow: "36h"}
+	// tuż po północy w Warszawie, czyli jeszcze poprzedniego dnia w UTC
+	midnight := gemConfig
+	midnight.Spec = "30 0 * * *"
+	// 31.10 10:00 w Warszawie to 09:00 UTC
+	monthEnd := time.Date(2026, 10, 31, 10, 0, 0, 0, warsaw)
+
+	tests := []struct {
+		name string
+		jc   JobConfig
+		last time.Time
+		now  time.Time
+		want time.Time
+	}{
+		{"koniec miesiąca w oknie", gemConfig, utc(time.October, 30, 8, 0), utc(time.November, 1, 8, 0), monthEnd},
+		{"koniec miesiąca poza oknem", gemConfig, utc(time.October, 30, 8, 0), utc(time.November, 2, 8, 0), time.Time{}},
+		{"zwykłe dni pomija Due", gemConfig, utc(time.October, 20, 8, 0), utc(time.October, 22, 12, 0), time.Time{}},
+		{"ostatnie uruchomienie po terminie", gemConfig, utc(time.October, 31, 9, 30), utc(time.November, 1, 8, 0), time.Time{}},
+		{"brak ostatniego uruchomienia", gemConfig, time.Time{}, utc(time.November, 1, 8, 0), monthEnd},
+		{"Due w strefie zadania", midnight, utc(time.October, 30, 8, 0), utc(time.October, 31, 6, 0), time.Date(2026, 10, 31, 0, 30, 0, 0, warsaw)},
+	}
+	for _, tt := range tests {
+		got, ok := missedRun(gem, tt.jc, tt.last, tt.now)
+		if ok != !tt.want.IsZero() || !got.Equal(tt.want) {
+			t.Errorf("%s: missedRun = %v, %v; want %v", tt.name, got, ok, tt.want)
+		}
+	}
+}
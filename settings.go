@@ -0,0 +1,196 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// WeatherUnits to jednostki przekazywane do Open-Meteo
+// (temperature_unit, wind_speed_unit, precipitation_unit). Puste pole oznacza domyślną.
+type WeatherUnits struct {
+	Temperature   string `json:"temperature,omitempty"`
+	WindSpeed     string `json:"wind_speed,omitempty"`
+	Precipitation string `json:"precipitation,omitempty"`
+}
+
+var metricUnits = WeatherUnits{Temperature: "celsius", WindSpeed: "kmh", Precipitation: "mm"}
+
+// merge uzupełnia puste pola wartościami z fallback.
+func (u WeatherUnits) merge(fallback WeatherUnits) WeatherUnits {
+	if u.Temperature == "" {
+		u.Temperature = fallback.Temperature
+	}
+	if u.WindSpeed == "" {
+		u.WindSpeed = fallback.WindSpeed
+	}
+	if u.Precipitation == "" {
+		u.Precipitation = fallback.Precipitation
+	}
+	return u
+}
+
+func (u WeatherUnits) tempSymbol() string {
+	if u.Temperature == "fahrenheit" {
+		return "°F"
+	}
+	return "°C"
+}
+
+func (u WeatherUnits) windSymbol() string {
+	if u.WindSpeed == "ms" {
+		return "m/s"
+	}
+	return "km/h"
+}
+
+func (u WeatherUnits) precipSymbol() string {
+	if u.Precipitation == "inch" {
+		return "in"
+	}
+	return "mm"
+}
+
+func (u WeatherUnits) describe(lang string) string {
+	return tr(lang, "units.describe", u.tempSymbol(), u.windSymbol(), u.precipSymbol())
+}
+
+// unitsFor zwraca jednostki użytkownika, a w ich braku ustawienia serwera i domyślne metryczne.
+func unitsFor(userID, guildID string) WeatherUnits {
+	configMu.Lock()
+	user := config.UserWeatherUnits[userID]
+	configMu.Unlock()
+	return user.merge(guildUnits(guildID))
+}
+
+func guildUnits(guildID string) WeatherUnits {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return config.GuildWeatherUnits[guildID].merge(metricUnits)
+}
+
+func parseUnitSetting(param, value string, u *WeatherUnits) error {
+	value = strings.ToLower(value)
+	switch param {
+	case "temp", "temperatura":
+		switch value {
+		case "c", "°c", "celsius":
+			u.Temperature = "celsius"
+		case "f", "°f", "fahrenheit":
+			u.Temperature = "fahrenheit"
+		default:
+			return trError("units.err.temp")
+		}
+	case "wiatr":
+		switch value {
+		case "kmh", "km/h":
+			u.WindSpeed = "kmh"
+		case "ms", "m/s":
+			u.WindSpeed = "ms"
+		default:
+			return trError("units.err.wind")
+		}
+	case "opady":
+		switch value {
+		case "mm":
+			u.Precipitation = "mm"
+		case "cale", "in", "inch":
+			u.Precipitation = "inch"
+		default:
+			return trError("units.err.precip")
+		}
+	default:
+		return trError("units.err.unknown", param)
+	}
+	return nil
+}
+
+func handleSettingsCommand(s *discordgo.Session, m *discordgo.MessageCreate, args string) {
+	lang := langFor(m.GuildID)
+	fields := strings.Fields(args)
+	if len(fields) > 0 && fields[0] == "wykres" {
+		handleChartThemeSetting(s, m, lang, fields[1:])
+		return
+	}
+	if len(fields) == 0 || fields[0] != "pogoda" {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "units.usage"))
+		return
+	}
+	fields = fields[1:]
+
+	guild := len(fields) > 0 && fields[0] == "serwer"
+	if guild {
+		fields = fields[1:]
+		if m.GuildID == "" {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "units.guild_only"))
+			return
+		}
+	}
+
+	if len(fields) == 0 {
+		msg := tr(lang, "units.current", unitsFor(m.Author.ID, m.GuildID).describe(lang), guildUnits(m.GuildID).describe(lang))
+		s.ChannelMessageSend(m.ChannelID, msg)
+		return
+	}
+	if len(fields)%2 != 0 {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "units.pairs"))
+		return
+	}
+
+	var u WeatherUnits
+	configMu.Lock()
+	if guild {
+		u = config.GuildWeatherUnits[m.GuildID]
+	} else {
+		u = config.UserWeatherUnits[m.Author.ID]
+	}
+	configMu.Unlock()
+	for i := 0; i < len(fields); i += 2 {
+		if err := parseUnitSetting(fields[i], fields[i+1], &u); err != nil {
+			s.ChannelMessageSend(m.ChannelID, "❌ "+localizeError(lang, err))
+			return
+		}
+	}
+
+	if guild {
+		updateConfig(func(c *Config) {
+			if c.GuildWeatherUnits == nil {
+				c.GuildWeatherUnits = make(map[string]WeatherUnits)
+			}
+			c.GuildWeatherUnits[m.GuildID] = u
+		})
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "units.guild_saved", guildUnits(m.GuildID).describe(lang)))
+		return
+	}
+	updateConfig(func(c *Config) {
+		if c.UserWeatherUnits == nil {
+			c.UserWeatherUnits = make(map[string]WeatherUnits)
+		}
+		c.UserWeatherUnits[m.Author.ID] = u
+	})
+	s.ChannelMessageSend(m.ChannelID, tr(lang, "units.user_saved", unitsFor(m.Author.ID, m.GuildID).describe(lang)))
+}
+
+// handleChartThemeSetting obsługuje `!ustawienia wykres [light|dark]` – domyślny motyw wykresów serwera.
+func handleChartThemeSetting(s *discordgo.Session, m *discordgo.MessageCreate, lang string, fields []string) {
+	if len(fields) == 0 {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "theme.current", chartThemeFor(m.GuildID)))
+		return
+	}
+	name, ok := parseChartTheme(fields[0])
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "theme.usage", strings.Join(chartThemeNames(), "|")))
+		return
+	}
+	if m.GuildID == "" {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "units.guild_only"))
+		return
+	}
+	updateConfig(func(c *Config) {
+		if c.GuildChartThemes == nil {
+			c.GuildChartThemes = make(map[string]string)
+		}
+		c.GuildChartThemes[m.GuildID] = name
+	})
+	s.ChannelMessageSend(m.ChannelID, tr(lang, "theme.set", name))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultShutdownTimeout = 30 * time.Second
+	healthShutdownTimeout  = 5 * time.Second
+)
+
+// shutdownTimeout czyta limit czasu zamykania z SHUTDOWN_TIMEOUT (np. "45s").
+func shutdownTimeout() time.Duration {
+	raw := os.Getenv("SHUTDOWN_TIMEOUT")
+	if raw == "" {
+		return defaultShutdownTimeout
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		slog.Warn("niepoprawny SHUTDOWN_TIMEOUT, używam domyślnego", "value", raw, "default", defaultShutdownTimeout)
+		return defaultShutdownTimeout
+	}
+	return d
+}
+
+// commandGate przepuszcza komendy do czasu rozpoczęcia zamykania
+// i pozwala poczekać na te, które już trwają (np. generowanie wykresu).
+type commandGate struct {
+	mu     sync.Mutex
+	closed bool
+	active sync.WaitGroup
+}
+
+func (g *commandGate) enter() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return false
+	}
+	g.active.Add(1)
+	return true
+}
+
+func (g *commandGate) close() {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+}
+
+// drained zamyka się, gdy skończą się wszystkie komendy wpuszczone przed close.
+func (g *commandGate) drained() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		g.active.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// handler opakowuje messageCreate: przekazuje kontekst i odrzuca wiadomości po rozpoczęciu zamykania.
+func (g *commandGate) handler(ctx context.Context) func(*discordgo.Session, *discordgo.MessageCreate) {
+	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if !g.enter() {
+			return
+		}
+		defer g.active.Done()
+		messageCreate(ctx, s, m)
+	}
+}
+
+// shutdown zamyka bota po kolei: przestaje przyjmować komendy, zatrzymuje crona,
+// czeka na trwające zadania i komendy, zapisuje konfigurację i rozłącza się z Discordem.
+// Po przekroczeniu limitu czasu anuluje kontekst, przerywając trwające operacje.
+func shutdown(cancel context.CancelFunc, dg *discordgo.Session, srv *http.Server, gate *commandGate, timeout time.Duration) {
+	slog.Info("zamykanie bota", "timeout", timeout)
+	started := time.Now()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	gate.close()
+	jobsDone := jobScheduler.stop()
+	commandsDone := gate.drained()
+
+	for jobsDone != nil || commandsDone != nil {
+		select {
+		case <-jobsDone:
+			slog.Info("cron zatrzymany, zadania zakończone")
+			jobsDone = nil
+		case <-commandsDone:
+			slog.Info("trwające komendy zakończone")
+			commandsDone = nil
+		case <-deadline.C:
+			slog.Warn("przekroczono limit czasu zamykania, przerywam trwające operacje", "timeout", timeout)
+			jobsDone, commandsDone = nil, nil
+		}
+	}
+	cancel()
+
+	if err := writeConfig(); err != nil {
+		slog.Error("błąd zapisu konfiguracji przy zamykaniu", "error", err)
+	}
+
+	if srv != nil {
+		ctx, cancelHealth := context.WithTimeout(context.Background(), healthShutdownTimeout)
+		if err := srv.Shutdown(ctx); err != nil {
+			slog.Warn("błąd zamykania serwera health", "error", err)
+		}
+		cancelHealth()
+	}
+
+	if err := dg.Close(); err != nil {
+		slog.Warn("błąd zamykania połączenia z Discordem", "error", err)
+	}
+	slog.Info("bot zamknięty", "duration", time.Since(started))
+}
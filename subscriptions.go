@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type WeatherSubscription struct {
+	UserID    string            `json:"user_id"`
+	ChannelID string            `json:"channel_id"`
+	GuildID   string            `json:"guild_id,omitempty"`
+	DM        bool              `json:"dm,omitempty"`
+	Locations []WeatherLocation `json:"locations"`
+}
+
+func (loc WeatherLocation) key() string {
+	return fmt.Sprintf("%.4f,%.4f", loc.Latitude, loc.Longitude)
+}
+
+// units zwraca jednostki dostawy: w DM preferencje użytkownika, na kanale ustawienia serwera.
+func (sub WeatherSubscription) units() WeatherUnits {
+	if sub.DM {
+		return unitsFor(sub.UserID, sub.GuildID)
+	}
+	return guildUnits(sub.GuildID)
+}
+
+func forecastKey(loc WeatherLocation, units WeatherUnits) string {
+	return loc.key() + "|" + units.Temperature + "," + units.WindSpeed + "," + units.Precipitation
+}
+
+func findWeatherSubscription(c *Config, userID string) (int, bool) {
+	for i, sub := range c.WeatherSubscriptions {
+		if sub.UserID == userID {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// weatherSubscriptions zwraca kopię subskrypcji, którą zadania mogą czytać bez blokady,
+// nawet gdy w tym czasie ktoś zmienia swoją listę miejscowości.
+func weatherSubscriptions() []WeatherSubscription {
+	configMu.Lock()
+	defer configMu.Unlock()
+	subs := make([]WeatherSubscription, len(config.WeatherSubscriptions))
+	for i, sub := range config.WeatherSubscriptions {
+		sub.Locations = append([]WeatherLocation(nil), sub.Locations...)
+		subs[i] = sub
+	}
+	return subs
+}
+
+func weatherSubscription(userID string) (WeatherSubscription, bool) {
+	for _, sub := range weatherSubscriptions() {
+		if sub.UserID == userID {
+			return sub, true
+		}
+	}
+	return WeatherSubscription{}, false
+}
+
+// resolveWeatherLocation szuka miejscowości najpierw na liście z konfiguracji, potem w geokoderze.
+func resolveWeatherLocation(ctx context.Context, name string) (WeatherLocation, error) {
+	if loc, _, ok := findWeatherLocation(name); ok {
+		return loc, nil
+	}
+	return weatherGeocoder.Geocode(ctx, name)
+}
+
+func handleWeatherSubscribe(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, lang, name string) {
+	loc, err := resolveWeatherLocation(ctx, name)
+	if err != nil {
+		msgLogger(m).Warn("błąd geokodowania", "command", "!pogoda zapisz", "location", name, "error", err)
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.not_found", name))
+		return
+	}
+
+	var sub WeatherSubscription
+	already := false
+	updateConfig(func(c *Config) {
+		i, ok := findWeatherSubscription(c, m.Author.ID)
+		if !ok {
+			c.WeatherSubscriptions = append(c.WeatherSubscriptions, WeatherSubscription{UserID: m.Author.ID})
+			i = len(c.WeatherSubscriptions) - 1
+		}
+		stored := &c.WeatherSubscriptions[i]
+		stored.ChannelID = m.ChannelID
+		stored.GuildID = m.GuildID
+		for _, existing := range stored.Locations {
+			if existing.key() == loc.key() {
+				already = true
+			}
+		}
+		if !already {
+			stored.Locations = append(stored.Locations, loc)
+		}
+		sub = *stored
+	})
+	if already {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.already", loc.Name))
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.added", loc.Name, describeDelivery(lang, sub)))
+}
+
+func handleWeatherUnsubscribe(s *discordgo.Session, m *discordgo.MessageCreate, lang, name string) {
+	if _, ok := weatherSubscription(m.Author.ID); !ok {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.none"))
+		return
+	}
+
+	found, removed := false, false
+	updateConfig(func(c *Config) {
+		i, ok := findWeatherSubscription(c, m.Author.ID)
+		if !ok {
+			return
+		}
+		found = true
+		if name == "" {
+			c.WeatherSubscriptions = append(c.WeatherSubscriptions[:i], c.WeatherSubscriptions[i+1:]...)
+			return
+		}
+		sub := &c.WeatherSubscriptions[i]
+		kept := make([]WeatherLocation, 0, len(sub.Locations))
+		for _, loc := range sub.Locations {
+			if strings.EqualFold(loc.Name, name) {
+				removed = true
+				continue
+			}
+			kept = append(kept, loc)
+		}
+		if !removed {
+			return
+		}
+		sub.Locations = kept
+		if len(sub.Locations) == 0 {
+			c.WeatherSubscriptions = append(c.WeatherSubscriptions[:i], c.WeatherSubscriptions[i+1:]...)
+		}
+	})
+	switch {
+	case !found:
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.none"))
+	case name == "":
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.cancelled"))
+	case !removed:
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.missing", name))
+	default:
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.removed", name))
+	}
+}
+
+func handleWeatherDelivery(s *discordgo.Session, m *discordgo.MessageCreate, lang, mode string) {
+	if _, ok := weatherSubscription(m.Author.ID); !ok {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.subscribe_first"))
+		return
+	}
+	if mode != "dm" && mode != "kanal" {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.delivery_usage"))
+		return
+	}
+
+	var sub WeatherSubscription
+	found := false
+	updateConfig(func(c *Config) {
+		i, ok := findWeatherSubscription(c, m.Author.ID)
+		if !ok {
+			return
+		}
+		found = true
+		stored := &c.WeatherSubscriptions[i]
+		stored.DM = mode == "dm"
+		if !stored.DM {
+			stored.ChannelID = m.ChannelID
+			stored.GuildID = m.GuildID
+		}
+		sub = *stored
+	})
+	if !found {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.subscribe_first"))
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, tr(lang, "subscription.delivery_set", describeDelivery(lang, sub)))
+}
+
+func buildWeatherSubscriptionInfo(lang, userID string) string {
+	sub, ok := weatherSubscription(userID)
+	if !ok {
+		return tr(lang, "subscription.none_hint")
+	}
+	names := make([]string, 0, len(sub.Locations))
+	for _, loc := range sub.Locations {
+		names = append(names, loc.Name)
+	}
+	return tr(lang, "subscription.info", strings.Join(names, ", "), describeDelivery(lang, sub))
+}
+
+func describeDelivery(lang string, sub WeatherSubscription) string {
+	if sub.DM {
+		return tr(lang, "subscription.delivery_dm")
+	}
+	return tr(lang, "subscription.delivery_channel", sub.ChannelID)
+}
+
+// sendWeatherSubscriptions pobiera prognozę raz dla każdej lokalizacji
+// i rozsyła ją zapisanym: wspólną wiadomością na kanał albo osobno w DM.
+// Błąd zwraca tylko przy pobieraniu, żeby ponowienie nie dublowało wysłanych już wiadomości.
+func sendWeatherSubscriptions(ctx context.Context, s *discordgo.Session) error {
+	subs := weatherSubscriptions()
+	if len(subs) == 0 {
+		return nil
+	}
+
+	forecasts := make(map[string]locationForecast)
+	for _, sub := range subs {
+		units := sub.units()
+		for _, loc := range sub.Locations {
+			key := forecastKey(loc, units)
+			if _, ok := forecasts[key]; ok {
+				continue
+			}
+			f, err := weatherAPI.fetchTomorrowForecast(ctx, loc.Latitude, loc.Longitude, units)
+			if err != nil {
+				return fmt.Errorf("prognoza dla %s: %w", loc.Name, err)
+			}
+			forecasts[key] = locationForecast{Name: loc.Name, forecast: f}
+		}
+	}
+
+	type channelDelivery struct {
+		lang     string
+		order    []string
+		mentions map[string][]string
+	}
+	channels := make(map[string]*channelDelivery)
+
+	for _, sub := range subs {
+		if len(sub.Locations) == 0 {
+			continue
+		}
+		if sub.DM {
+			own := make([]locationForecast, 0, len(sub.Locations))
+			for _, loc := range sub.Locations {
+				own = append(own, forecasts[forecastKey(loc, sub.units())])
+			}
+			ch, err := s.UserChannelCreate(sub.UserID)
+			if err == nil {
+				err = sendRich(s, ch.ID, weatherMessage(langFor(sub.GuildID), own))
+			}
+			if err != nil {
+				slog.Error("błąd wysyłki prognozy DM", "job", "pogoda", "user", sub.UserID, "error", err)
+			}
+			continue
+		}
+
+		cd, ok := channels[sub.ChannelID]
+		if !ok {
+			cd = &channelDelivery{lang: langFor(sub.GuildID), mentions: make(map[string][]string)}
+			channels[sub.ChannelID] = cd
+		}
+		for _, loc := range sub.Locations {
+			key := forecastKey(loc, sub.units())
+			if _, seen := cd.mentions[key]; !seen {
+				cd.order = append(cd.order, key)
+			}
+			cd.mentions[key] = append(cd.mentions[key], "<@"+sub.UserID+">")
+		}
+	}
+
+	channelIDs := make([]string, 0, len(channels))
+	for id := range channels {
+		channelIDs = append(channelIDs, id)
+	}
+	sort.Strings(channelIDs)
+
+	for _, channelID := range channelIDs {
+		cd := channels[channelID]
+		list := make([]locationForecast, 0, len(cd.order))
+		var content strings.Builder
+		for _, key := range cd.order {
+			f := forecasts[key]
+			list = append(list, f)
+			if content.Len() > 0 {
+				content.WriteString("\n")
+			}
+			content.WriteString(fmt.Sprintf("📍 %s: %s", f.Name, strings.Join(cd.mentions[key], " ")))
+		}
+		msg := weatherMessage(cd.lang, list)
+		msg.Content = content.String()
+		if err := sendRich(s, channelID, msg); err != nil {
+			slog.Error("błąd wysyłki prognozy", "job", "pogoda", "channel", channelID, "error", err)
+		}
+	}
+	return nil
+}
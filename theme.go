@@ -0,0 +1,197 @@
+package main
+
+import (
+	"image/color"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+const defaultChartTheme = "light"
+
+// chartTheme opisuje wygląd wykresu: tło, siatkę, osie, tekst, czcionki i grubości linii.
+type chartTheme struct {
+	Background color.Color
+	Text       color.Color
+	Axis       color.Color
+	Grid       color.Color
+	Font       font.Font
+	TitleSize  vg.Length
+	LabelSize  vg.Length // etykiety osi i legenda
+	TickSize   vg.Length
+	LineWidth  vg.Length
+	AxisWidth  vg.Length
+	GridWidth  vg.Length
+	// Series nadpisuje kolory serii według tickera; brakujące biorą się z gemColors.
+	Series map[string]color.Color
+	// Palette to kolory dla tickerów spoza Series i gemColors, przydzielane po kolei.
+	Palette []color.Color
+}
+
+var chartThemes = map[string]chartTheme{
+	"light": {
+		Background: color.White,
+		Text:       color.Black,
+		Axis:       color.Black,
+		Grid:       color.Gray{Y: 128},
+		Font:       plot.DefaultFont,
+		TitleSize:  vg.Points(12),
+		LabelSize:  vg.Points(12),
+		TickSize:   vg.Points(10),
+		LineWidth:  vg.Points(1.5),
+		AxisWidth:  vg.Points(0.5),
+		GridWidth:  vg.Points(0.25),
+		Palette: []color.Color{
+			hexColor("1F77B4"), hexColor("FF7F0E"), hexColor("2CA02C"), hexColor("D62728"),
+			hexColor("9467BD"), hexColor("8C564B"), hexColor("E377C2"), hexColor("17BECF"),
+		},
+	},
+	// dark pasuje do ciemnego motywu Discorda; serie są rozjaśnione, żeby nie ginęły na tle.
+	"dark": {
+		Background: hexColor("313338"),
+		Text:       hexColor("DBDEE1"),
+		Axis:       hexColor("949BA4"),
+		Grid:       hexColor("4E5058"),
+		Font:       plot.DefaultFont,
+		TitleSize:  vg.Points(12),
+		LabelSize:  vg.Points(12),
+		TickSize:   vg.Points(10),
+		LineWidth:  vg.Points(2),
+		AxisWidth:  vg.Points(0.5),
+		GridWidth:  vg.Points(0.25),
+		Series: map[string]color.Color{
+			"EIMI.L": hexColor("5DADFF"),
+			"CNDX.L": hexColor("FFB347"),
+			"CBU0.L": hexColor("57D977"),
+			"IB01.L": hexColor("FF6B6B"),
+		},
+		Palette: []color.Color{
+			hexColor("5DADFF"), hexColor("FFB347"), hexColor("57D977"), hexColor("FF6B6B"),
+			hexColor("C39BFF"), hexColor("F2D16B"), hexColor("FF8AD8"), hexColor("4DE1E1"),
+		},
+	},
+}
+
+func chartThemeNames() []string {
+	names := make([]string, 0, len(chartThemes))
+	for name := range chartThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// chartThemeFor zwraca motyw ustawiony dla serwera albo domyślny jasny.
+func chartThemeFor(guildID string) string {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if name, ok := config.GuildChartThemes[guildID]; ok {
+		if _, known := chartThemes[name]; known {
+			return name
+		}
+	}
+	return defaultChartTheme
+}
+
+func (t chartTheme) seriesColor(ticker string) color.Color {
+	if c, ok := t.Series[ticker]; ok {
+		return c
+	}
+	if c, ok := gemColors[ticker]; ok {
+		return c
+	}
+	return t.Text
+}
+
+// seriesColors przydziela kolory tickerom: najpierw stałe z seriesColor, potem kolejne
+// nieużyte kolory z palety. Przy większej liczbie serii niż kolorów paleta się powtarza.
+func (t chartTheme) seriesColors(tickers []string) map[string]color.Color {
+	colors := make(map[string]color.Color, len(tickers))
+	used := make(map[color.Color]bool)
+	var rest []string
+	for _, ticker := range tickers {
+		if t.hasSeriesColor(ticker) {
+			colors[ticker] = t.seriesColor(ticker)
+			used[colors[ticker]] = true
+		} else {
+			rest = append(rest, ticker)
+		}
+	}
+
+	free := make([]color.Color, 0, len(t.Palette))
+	for _, c := range t.Palette {
+		if !used[c] {
+			free = append(free, c)
+		}
+	}
+	if len(free) == 0 {
+		free = t.Palette
+	}
+	for i, ticker := range rest {
+		if len(free) == 0 {
+			colors[ticker] = t.Text
+			continue
+		}
+		colors[ticker] = free[i%len(free)]
+	}
+	return colors
+}
+
+func (t chartTheme) hasSeriesColor(ticker string) bool {
+	if _, ok := t.Series[ticker]; ok {
+		return true
+	}
+	_, ok := gemColors[ticker]
+	return ok
+}
+
+// apply ustawia kolory i czcionki tła, tytułu, osi i legendy.
+func (t chartTheme) apply(p *plot.Plot) {
+	p.BackgroundColor = t.Background
+
+	p.Title.TextStyle.Color = t.Text
+	p.Title.TextStyle.Font = font.From(t.Font, t.TitleSize)
+	p.Legend.TextStyle.Color = t.Text
+	p.Legend.TextStyle.Font = font.From(t.Font, t.LabelSize)
+
+	for _, axis := range []*plot.Axis{&p.X, &p.Y} {
+		axis.Label.TextStyle.Color = t.Text
+		axis.Label.TextStyle.Font = font.From(t.Font, t.LabelSize)
+		axis.Tick.Label.Color = t.Text
+		axis.Tick.Label.Font = font.From(t.Font, t.TickSize)
+		axis.LineStyle.Color = t.Axis
+		axis.LineStyle.Width = t.AxisWidth
+		axis.Tick.LineStyle.Color = t.Axis
+		axis.Tick.LineStyle.Width = t.AxisWidth
+	}
+}
+
+func (t chartTheme) grid() *plotter.Grid {
+	g := plotter.NewGrid()
+	g.Vertical.Color = t.Grid
+	g.Vertical.Width = t.GridWidth
+	g.Horizontal.Color = t.Grid
+	g.Horizontal.Width = t.GridWidth
+	return g
+}
+
+func isChartTheme(name string) bool {
+	_, ok := parseChartTheme(name)
+	return ok
+}
+
+// parseChartTheme rozpoznaje nazwę motywu, także po polsku (jasny, ciemny).
+func parseChartTheme(name string) (string, bool) {
+	switch name = strings.ToLower(name); name {
+	case "jasny":
+		return "light", true
+	case "ciemny":
+		return "dark", true
+	}
+	_, ok := chartThemes[name]
+	return name, ok
+}
@@ -0,0 +1,470 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type WeatherLocation struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Country   string  `json:"country,omitempty"`
+}
+
+var defaultWeatherLocations = []WeatherLocation{
+	{Name: "Leśna", Latitude: 51.0156, Longitude: 15.2634, Country: "Polska"},
+	{Name: "Bielsko-Biała", Latitude: 49.8224, Longitude: 19.0469, Country: "Polska"},
+}
+
+type weatherResponse struct {
+	Daily struct {
+		Time           []string  `json:"time"`
+		TemperatureMax []float64 `json:"temperature_2m_max"`
+		TemperatureMin []float64 `json:"temperature_2m_min"`
+		WeatherCode    []int     `json:"weathercode"`
+		PrecipSum      []float64 `json:"precipitation_sum"`
+		PrecipProbMax  []float64 `json:"precipitation_probability_max"`
+		WindSpeedMax   []float64 `json:"wind_speed_10m_max"`
+		WindGustsMax   []float64 `json:"wind_gusts_10m_max"`
+		UVIndexMax     []float64 `json:"uv_index_max"`
+		Sunrise        []string  `json:"sunrise"`
+		Sunset         []string  `json:"sunset"`
+	} `json:"daily"`
+	Hourly struct {
+		Time                     []string  `json:"time"`
+		Temperature              []float64 `json:"temperature_2m"`
+		PrecipitationProbability []float64 `json:"precipitation_probability"`
+		WindSpeed                []float64 `json:"wind_speed_10m"`
+	} `json:"hourly"`
+}
+
+func (w weatherResponse) day(i int) (forecast, bool) {
+	d := w.Daily
+	if i < 0 || i >= len(d.Time) || i >= len(d.TemperatureMax) || i >= len(d.TemperatureMin) || i >= len(d.WeatherCode) {
+		return forecast{}, false
+	}
+	f := forecast{
+		Date:    d.Time[i],
+		TempMax: d.TemperatureMax[i],
+		TempMin: d.TemperatureMin[i],
+		Code:    d.WeatherCode[i],
+	}
+	if i < len(d.PrecipSum) {
+		f.Precip = d.PrecipSum[i]
+	}
+	if i < len(d.PrecipProbMax) {
+		f.PrecipChance = d.PrecipProbMax[i]
+	}
+	if i < len(d.WindSpeedMax) {
+		f.Wind = d.WindSpeedMax[i]
+	}
+	if i < len(d.WindGustsMax) {
+		f.Gust = d.WindGustsMax[i]
+	}
+	if i < len(d.UVIndexMax) {
+		f.UVIndex = d.UVIndexMax[i]
+	}
+	if i < len(d.Sunrise) {
+		f.Sunrise = clockPart(d.Sunrise[i])
+	}
+	if i < len(d.Sunset) {
+		f.Sunset = clockPart(d.Sunset[i])
+	}
+	return f, true
+}
+
+type forecast struct {
+	TempMin      float64
+	TempMax      float64
+	Code         int
+	Date         string
+	Precip       float64
+	PrecipChance float64
+	Wind         float64
+	Gust         float64
+	UVIndex      float64
+	Sunrise      string
+	Sunset       string
+	Units        WeatherUnits
+}
+
+// clockPart wycina godzinę z czasu ISO 8601 w formacie Open-Meteo ("2026-10-19T06:58").
+func clockPart(ts string) string {
+	if _, clock, ok := strings.Cut(ts, "T"); ok {
+		return clock
+	}
+	return ts
+}
+
+type hourlyForecast struct {
+	Time         time.Time
+	Temp         float64
+	PrecipChance float64
+	WindSpeed    float64
+	Units        WeatherUnits
+}
+
+type locationForecast struct {
+	Name string
+	forecast
+}
+
+func buildTomorrowWeatherMessage(ctx context.Context, lang string, units WeatherUnits) (richMessage, error) {
+	locations := weatherLocations()
+	if len(locations) == 0 {
+		return richMessage{}, fmt.Errorf("brak lokalizacji pogodowych")
+	}
+	return buildWeatherMessage(ctx, lang, locations, units)
+}
+
+func buildWeatherMessage(ctx context.Context, lang string, locations []WeatherLocation, units WeatherUnits) (richMessage, error) {
+	forecasts := make([]locationForecast, 0, len(locations))
+	for _, loc := range locations {
+		f, err := weatherAPI.fetchTomorrowForecast(ctx, loc.Latitude, loc.Longitude, units)
+		if err != nil {
+			slog.Error("błąd prognozy", "location", loc.Name, "error", err)
+			return richMessage{}, err
+		}
+		forecasts = append(forecasts, locationForecast{Name: loc.Name, forecast: f})
+	}
+	return weatherMessage(lang, forecasts), nil
+}
+
+func (c *weatherClient) fetchTomorrowForecast(ctx context.Context, lat, lon float64, units WeatherUnits) (forecast, error) {
+	parsed, err := c.fetch(ctx, lat, lon, units)
+	if err != nil {
+		return forecast{}, err
+	}
+	f, ok := parsed.day(1)
+	if !ok {
+		return forecast{}, fmt.Errorf("insufficient forecast data")
+	}
+	f.Units = units.merge(metricUnits)
+	return f, nil
+}
+
+func (c *weatherClient) fetchDailyForecast(ctx context.Context, lat, lon float64, days int, units WeatherUnits) ([]forecast, error) {
+	parsed, err := c.fetch(ctx, lat, lon, units)
+	if err != nil {
+		return nil, err
+	}
+	if days > len(parsed.Daily.Time) {
+		days = len(parsed.Daily.Time)
+	}
+	out := make([]forecast, 0, days)
+	for i := 0; i < days; i++ {
+		f, ok := parsed.day(i)
+		if !ok {
+			return nil, fmt.Errorf("insufficient forecast data")
+		}
+		f.Units = units.merge(metricUnits)
+		out = append(out, f)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("insufficient forecast data")
+	}
+	return out, nil
+}
+
+// fetchTomorrowHourly zwraca prognozę godzinową na jutro (czas lokalny Europe/Warsaw).
+func (c *weatherClient) fetchTomorrowHourly(ctx context.Context, lat, lon float64, units WeatherUnits) ([]hourlyForecast, error) {
+	parsed, err := c.fetch(ctx, lat, lon, units)
+	if err != nil {
+		return nil, err
+	}
+	tomorrow, ok := parsed.day(1)
+	if !ok {
+		return nil, fmt.Errorf("insufficient forecast data")
+	}
+	loc, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		return nil, err
+	}
+
+	h := parsed.Hourly
+	if len(h.Temperature) < len(h.Time) || len(h.PrecipitationProbability) < len(h.Time) || len(h.WindSpeed) < len(h.Time) {
+		return nil, fmt.Errorf("insufficient hourly data")
+	}
+	hours := make([]hourlyForecast, 0, 24)
+	for i, ts := range h.Time {
+		if !strings.HasPrefix(ts, tomorrow.Date) {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02T15:04", ts, loc)
+		if err != nil {
+			return nil, err
+		}
+		hours = append(hours, hourlyForecast{
+			Time:         t,
+			Temp:         h.Temperature[i],
+			PrecipChance: h.PrecipitationProbability[i],
+			WindSpeed:    h.WindSpeed[i],
+			Units:        units.merge(metricUnits),
+		})
+	}
+	if len(hours) == 0 {
+		return nil, fmt.Errorf("insufficient hourly data")
+	}
+	return hours, nil
+}
+
+// geocoder zamienia nazwę miejscowości na współrzędne.
+type geocoder interface {
+	Geocode(ctx context.Context, name string) (WeatherLocation, error)
+}
+
+var weatherGeocoder geocoder = openMeteoGeocoder{
+	client:  &http.Client{Timeout: 10 * time.Second},
+	baseURL: "https://geocoding-api.open-meteo.com/v1/search",
+}
+
+type openMeteoGeocoder struct {
+	client  *http.Client
+	baseURL string
+}
+
+type geocodingResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Country   string  `json:"country"`
+	} `json:"results"`
+}
+
+func (g openMeteoGeocoder) Geocode(ctx context.Context, name string) (loc WeatherLocation, err error) {
+	started := time.Now()
+	defer func() { observeAPI("open-meteo-geocoding", time.Since(started), err) }()
+
+	q := url.Values{}
+	q.Set("name", name)
+	q.Set("count", "1")
+	q.Set("language", "pl")
+	q.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return WeatherLocation{}, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return WeatherLocation{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return WeatherLocation{}, fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	var parsed geocodingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return WeatherLocation{}, err
+	}
+	if len(parsed.Results) == 0 {
+		return WeatherLocation{}, fmt.Errorf("nie znaleziono miejscowości %q", name)
+	}
+	r := parsed.Results[0]
+	return WeatherLocation{
+		Name:      r.Name,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+		Country:   r.Country,
+	}, nil
+}
+
+// staticGeocoder to zaślepka geokodera dla testów i pracy bez sieci.
+type staticGeocoder map[string]WeatherLocation
+
+func (g staticGeocoder) Geocode(ctx context.Context, name string) (WeatherLocation, error) {
+	if loc, ok := g[strings.ToLower(name)]; ok {
+		return loc, nil
+	}
+	return WeatherLocation{}, fmt.Errorf("nie znaleziono miejscowości %q", name)
+}
+
+func findWeatherLocation(name string) (WeatherLocation, int, bool) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	return findLocationIn(config.WeatherLocations, name)
+}
+
+func findLocationIn(locations []WeatherLocation, name string) (WeatherLocation, int, bool) {
+	for i, loc := range locations {
+		if strings.EqualFold(loc.Name, name) {
+			return loc, i, true
+		}
+	}
+	return WeatherLocation{}, -1, false
+}
+
+func handleWeatherCommand(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, args string) {
+	args = strings.TrimSpace(args)
+	cmd, rest, _ := strings.Cut(args, " ")
+	rest = strings.TrimSpace(rest)
+	units := unitsFor(m.Author.ID, m.GuildID)
+	lang := langFor(m.GuildID)
+
+	switch {
+	case args == "":
+		msg, err := buildTomorrowWeatherMessage(ctx, lang, units)
+		if err != nil {
+			recordError("weather")
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.fetch_failed"))
+			return
+		}
+		sendRich(s, m.ChannelID, msg)
+	case cmd == "zapisz" && rest != "":
+		handleWeatherSubscribe(ctx, s, m, lang, rest)
+	case cmd == "wypisz":
+		handleWeatherUnsubscribe(s, m, lang, rest)
+	case cmd == "dostawa":
+		handleWeatherDelivery(s, m, lang, rest)
+	case cmd == "moje":
+		s.ChannelMessageSend(m.ChannelID, buildWeatherSubscriptionInfo(lang, m.Author.ID))
+	case cmd == "7d":
+		handleWeekForecast(ctx, s, m, lang, rest, units)
+	case cmd == "godzinowo":
+		handleHourlyForecast(ctx, s, m, lang, rest, units)
+	case cmd == "progi":
+		handleWeatherThresholds(s, m, lang, rest)
+	case cmd == "lista":
+		s.ChannelMessageSend(m.ChannelID, buildWeatherLocationList(lang))
+	case cmd == "dodaj" && rest != "":
+		loc, err := weatherGeocoder.Geocode(ctx, rest)
+		if err != nil {
+			msgLogger(m).Warn("błąd geokodowania", "command", "!pogoda dodaj", "location", rest, "error", err)
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.not_found", rest))
+			return
+		}
+		exists := false
+		updateConfig(func(c *Config) {
+			if _, _, exists = findLocationIn(c.WeatherLocations, loc.Name); !exists {
+				c.WeatherLocations = append(c.WeatherLocations, loc)
+			}
+		})
+		if exists {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.location_exists", loc.Name))
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.location_added", loc.Name, loc.Country, loc.Latitude, loc.Longitude))
+	case cmd == "usun" && rest != "":
+		found := false
+		updateConfig(func(c *Config) {
+			var i int
+			if _, i, found = findLocationIn(c.WeatherLocations, rest); found {
+				c.WeatherLocations = append(c.WeatherLocations[:i], c.WeatherLocations[i+1:]...)
+			}
+		})
+		if !found {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.location_missing", rest))
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.location_removed", rest))
+	case cmd == "dodaj" || cmd == "usun":
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.location_usage"))
+	default:
+		loc, _, ok := findWeatherLocation(args)
+		if !ok {
+			var err error
+			loc, err = weatherGeocoder.Geocode(ctx, args)
+			if err != nil {
+				msgLogger(m).Warn("błąd geokodowania", "command", "!pogoda", "location", args, "error", err)
+				s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.not_found", args))
+				return
+			}
+		}
+		msg, err := buildWeatherMessage(ctx, lang, []WeatherLocation{loc}, units)
+		if err != nil {
+			recordError("weather")
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "weather.fetch_failed"))
+			return
+		}
+		sendRich(s, m.ChannelID, msg)
+	}
+}
+
+func buildWeatherLocationList(lang string) string {
+	locations := weatherLocations()
+	if len(locations) == 0 {
+		return tr(lang, "weather.no_locations")
+	}
+	var b strings.Builder
+	b.WriteString(tr(lang, "weather.locations_header"))
+	for i, loc := range locations {
+		b.WriteString(fmt.Sprintf("\n%d. %s", i+1, loc.Name))
+		if loc.Country != "" {
+			b.WriteString(", " + loc.Country)
+		}
+		b.WriteString(fmt.Sprintf(" (%.4f, %.4f)", loc.Latitude, loc.Longitude))
+	}
+	return b.String()
+}
+
+type weatherSeverity int
+
+const (
+	severityNone weatherSeverity = iota
+	severityLow
+	severityModerate
+	severityHigh
+)
+
+type weatherCondition struct {
+	Emoji    string
+	Severity weatherSeverity
+}
+
+// weatherCodes opisuje kody pogody WMO zwracane przez Open-Meteo.
+// Opisy słowne są w katalogach tłumaczeń pod kluczami weather.code.<kod>.
+var weatherCodes = map[int]weatherCondition{
+	0:  {"☀️", severityNone},
+	1:  {"🌤️", severityNone},
+	2:  {"⛅", severityNone},
+	3:  {"☁️", severityNone},
+	45: {"🌫️", severityLow},
+	48: {"🌫️", severityModerate},
+	51: {"🌦️", severityLow},
+	53: {"🌦️", severityLow},
+	55: {"🌧️", severityLow},
+	56: {"🧊", severityModerate},
+	57: {"🧊", severityHigh},
+	61: {"🌦️", severityLow},
+	63: {"🌧️", severityLow},
+	65: {"🌧️", severityModerate},
+	66: {"🧊", severityModerate},
+	67: {"🧊", severityHigh},
+	71: {"🌨️", severityLow},
+	73: {"🌨️", severityModerate},
+	75: {"❄️", severityHigh},
+	77: {"🌨️", severityLow},
+	80: {"🌦️", severityLow},
+	81: {"🌧️", severityModerate},
+	82: {"🌧️", severityHigh},
+	85: {"🌨️", severityLow},
+	86: {"❄️", severityModerate},
+	95: {"⛈️", severityHigh},
+	96: {"⛈️", severityHigh},
+	99: {"⛈️", severityHigh},
+}
+
+func weatherCodeInfo(code int) weatherCondition {
+	if c, ok := weatherCodes[code]; ok {
+		return c
+	}
+	return weatherCondition{Emoji: "🌡️"}
+}
+
+func weatherDescription(lang string, code int) string {
+	if _, ok := weatherCodes[code]; !ok {
+		return tr(lang, "weather.code.unknown")
+	}
+	return tr(lang, fmt.Sprintf("weather.code.%d", code))
+}
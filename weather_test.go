@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// fakeDiscord przechwytuje wiadomości wysyłane przez sesję zamiast łączyć się z API.
+type fakeDiscord struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (f *fakeDiscord) RoundTrip(req *http.Request) (*http.Response, error) {
+	var msg discordgo.MessageSend
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if mediaType == "multipart/form-data" {
+			r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+			if part, err := r.NextPart(); err == nil {
+				json.NewDecoder(part).Decode(&msg)
+			}
+		} else {
+			json.Unmarshal(body, &msg)
+		}
+	}
+	f.mu.Lock()
+	f.messages = append(f.messages, msg.Content)
+	f.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"id":"1","channel_id":"c1"}`)),
+		Request:    req,
+	}, nil
+}
+
+// last zwraca ostatnią wysłaną wiadomość.
+func (f *fakeDiscord) last() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.messages) == 0 {
+		return ""
+	}
+	return f.messages[len(f.messages)-1]
+}
+
+func newFakeSession(t *testing.T) (*discordgo.Session, *fakeDiscord) {
+	t.Helper()
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeDiscord{}
+	s.Client = &http.Client{Transport: fake}
+	return s, fake
+}
+
+func testMessage(content string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:        "m1",
+		ChannelID: "c1",
+		Content:   content,
+		Author:    &discordgo.User{ID: "u1"},
+	}}
+}
+
+func TestWeatherLocationCommands(t *testing.T) {
+	useTempConfig(t)
+	config.WeatherLocations = []WeatherLocation{{Name: "Warszawa", Latitude: 52.2297, Longitude: 21.0122, Country: "Polska"}}
+	prev := weatherGeocoder
+	weatherGeocoder = staticGeocoder{
+		"kraków": {Name: "Kraków", Latitude: 50.0614, Longitude: 19.9366, Country: "Polska"},
+	}
+	t.Cleanup(func() { weatherGeocoder = prev })
+
+	s, fake := newFakeSession(t)
+	ctx := context.Background()
+	lang := defaultLanguage
+	steps := []struct {
+		args  string
+		reply string
+		names []string
+	}{
+		{"dodaj Kraków", tr(lang, "weather.location_added", "Kraków", "Polska", 50.0614, 19.9366), []string{"Warszawa", "Kraków"}},
+		{"dodaj kraków", tr(lang, "weather.location_exists", "Kraków"), []string{"Warszawa", "Kraków"}},
+		{"dodaj Atlantyda", tr(lang, "weather.not_found", "Atlantyda"), []string{"Warszawa", "Kraków"}},
+		{"usun warszawa", tr(lang, "weather.location_removed", "warszawa"), []string{"Kraków"}},
+		{"usun Gdańsk", tr(lang, "weather.location_missing", "Gdańsk"), []string{"Kraków"}},
+		{"dodaj", tr(lang, "weather.location_usage"), []string{"Kraków"}},
+		{"usun ", tr(lang, "weather.location_usage"), []string{"Kraków"}},
+		{"lista", "", []string{"Kraków"}},
+	}
+	for _, step := range steps {
+		handleWeatherCommand(ctx, s, testMessage("!pogoda "+step.args), step.args)
+		if got := fake.last(); step.reply != "" && got != step.reply {
+			t.Errorf("!pogoda %s: odpowiedź %q, want %q", step.args, got, step.reply)
+		}
+		var names []string
+		for _, loc := range weatherLocations() {
+			names = append(names, loc.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(step.names, ",") {
+			t.Errorf("!pogoda %s: lokalizacje %v, want %v", step.args, names, step.names)
+		}
+	}
+	if !strings.Contains(fake.last(), "Kraków, Polska") || strings.Contains(fake.last(), "Warszawa") {
+		t.Errorf("lista = %q", fake.last())
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// WeatherThresholds to progi ostrzeżeń dla jednej lokalizacji, zawsze w jednostkach metrycznych.
+type WeatherThresholds struct {
+	FrostC   float64 `json:"frost_c"`
+	HeatC    float64 `json:"heat_c"`
+	PrecipMM float64 `json:"precip_mm"`
+	GustKmh  float64 `json:"gust_kmh"`
+	Storm    bool    `json:"storm"`
+}
+
+var defaultWeatherThresholds = WeatherThresholds{
+	FrostC:   -5,
+	HeatC:    30,
+	PrecipMM: 20,
+	GustKmh:  70,
+	Storm:    true,
+}
+
+// weatherAlertDays to liczba dni (licząc dziś), dla których sprawdzamy ostrzeżenia.
+const weatherAlertDays = 3
+
+type weatherAlert struct {
+	Kind  string
+	Date  string
+	Value float64
+	Code  int
+}
+
+// describe opisuje ostrzeżenie w danym języku; wartości są zawsze metryczne.
+func (a weatherAlert) describe(lang string) string {
+	if a.Kind == "burza" {
+		return weatherCodeInfo(a.Code).Emoji + " " + weatherDescription(lang, a.Code)
+	}
+	return tr(lang, "alert."+a.Kind, a.Value)
+}
+
+func thresholdsFor(name string) WeatherThresholds {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if t, ok := config.WeatherThresholds[strings.ToLower(name)]; ok {
+		return t
+	}
+	return defaultWeatherThresholds
+}
+
+func isStormCode(code int) bool {
+	return code >= 95 && code <= 99
+}
+
+func evaluateWeatherAlerts(f forecast, t WeatherThresholds) []weatherAlert {
+	var alerts []weatherAlert
+	if f.TempMin <= t.FrostC {
+		alerts = append(alerts, weatherAlert{Kind: "mroz", Date: f.Date, Value: f.TempMin})
+	}
+	if f.TempMax >= t.HeatC {
+		alerts = append(alerts, weatherAlert{Kind: "upal", Date: f.Date, Value: f.TempMax})
+	}
+	if t.PrecipMM > 0 && f.Precip >= t.PrecipMM {
+		alerts = append(alerts, weatherAlert{Kind: "opady", Date: f.Date, Value: f.Precip})
+	}
+	if t.GustKmh > 0 && f.Gust >= t.GustKmh {
+		alerts = append(alerts, weatherAlert{Kind: "porywy", Date: f.Date, Value: f.Gust})
+	}
+	if t.Storm && isStormCode(f.Code) {
+		alerts = append(alerts, weatherAlert{Kind: "burza", Date: f.Date, Code: f.Code})
+	}
+	return alerts
+}
+
+func weatherAlertKey(loc WeatherLocation, a weatherAlert) string {
+	return loc.key() + "|" + a.Date + "|" + a.Kind
+}
+
+// checkWeatherAlerts sprawdza prognozę dla lokalizacji z subskrypcji i wysyła
+// ostrzeżenia o przekroczonych progach. To samo zdarzenie (miejsce, dzień, rodzaj)
+// jest zgłaszane tylko raz.
+func checkWeatherAlerts(ctx context.Context, s *discordgo.Session, now time.Time) error {
+	type target struct {
+		loc          WeatherLocation
+		dms          []WeatherSubscription
+		byChannel    map[string][]string
+		channelIDs   []string
+		channelLangs map[string]string
+	}
+	targets := make(map[string]*target)
+	var order []string
+	for _, sub := range weatherSubscriptions() {
+		for _, loc := range sub.Locations {
+			t, ok := targets[loc.key()]
+			if !ok {
+				t = &target{loc: loc, byChannel: make(map[string][]string), channelLangs: make(map[string]string)}
+				targets[loc.key()] = t
+				order = append(order, loc.key())
+			}
+			if sub.DM {
+				t.dms = append(t.dms, sub)
+				continue
+			}
+			if _, seen := t.byChannel[sub.ChannelID]; !seen {
+				t.channelIDs = append(t.channelIDs, sub.ChannelID)
+				t.channelLangs[sub.ChannelID] = langFor(sub.GuildID)
+			}
+			t.byChannel[sub.ChannelID] = append(t.byChannel[sub.ChannelID], "<@"+sub.UserID+">")
+		}
+	}
+
+	pruneSentWeatherAlerts(now)
+	sentAlerts := readConfig(func(c *Config) map[string]time.Time { return maps.Clone(c.SentWeatherAlerts) })
+
+	var fetchErrs []string
+	for _, key := range order {
+		t := targets[key]
+		days, err := weatherAPI.fetchDailyForecast(ctx, t.loc.Latitude, t.loc.Longitude, weatherAlertDays, metricUnits)
+		if err != nil {
+			fetchErrs = append(fetchErrs, fmt.Sprintf("%s: %v", t.loc.Name, err))
+			continue
+		}
+
+		thresholds := thresholdsFor(t.loc.Name)
+		var fresh []weatherAlert
+		for _, day := range days {
+			for _, a := range evaluateWeatherAlerts(day, thresholds) {
+				if _, sent := sentAlerts[weatherAlertKey(t.loc, a)]; sent {
+					continue
+				}
+				fresh = append(fresh, a)
+			}
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+
+		for _, channelID := range t.channelIDs {
+			text := formatWeatherAlerts(t.channelLangs[channelID], t.loc.Name, fresh)
+			content := strings.Join(t.byChannel[channelID], " ") + "\n" + text
+			if _, err := s.ChannelMessageSend(channelID, content); err != nil {
+				slog.Error("błąd wysyłki ostrzeżenia", "job", "ostrzezenia", "channel", channelID, "error", err)
+			}
+		}
+		for _, sub := range t.dms {
+			ch, err := s.UserChannelCreate(sub.UserID)
+			if err == nil {
+				_, err = s.ChannelMessageSend(ch.ID, formatWeatherAlerts(langFor(sub.GuildID), t.loc.Name, fresh))
+			}
+			if err != nil {
+				slog.Error("błąd wysyłki ostrzeżenia DM", "job", "ostrzezenia", "user", sub.UserID, "error", err)
+			}
+		}
+		markWeatherAlertsSent(t.loc, fresh, now)
+	}
+
+	if len(fetchErrs) > 0 {
+		return trError("alert.fetch_failed", strings.Join(fetchErrs, "; "))
+	}
+	return nil
+}
+
+func formatWeatherAlerts(lang, name string, alerts []weatherAlert) string {
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Date < alerts[j].Date })
+	var b strings.Builder
+	b.WriteString(tr(lang, "alert.title", name))
+	for _, a := range alerts {
+		b.WriteString(fmt.Sprintf("\n%s: %s", forecastDayLabel(lang, a.Date), a.describe(lang)))
+	}
+	return b.String()
+}
+
+func markWeatherAlertsSent(loc WeatherLocation, alerts []weatherAlert, now time.Time) {
+	configMu.Lock()
+	if config.SentWeatherAlerts == nil {
+		config.SentWeatherAlerts = make(map[string]time.Time)
+	}
+	for _, a := range alerts {
+		config.SentWeatherAlerts[weatherAlertKey(loc, a)] = now
+	}
+	configMu.Unlock()
+	saveConfig()
+}
+
+// pruneSentWeatherAlerts usuwa wpisy o zdarzeniach, które już minęły.
+func pruneSentWeatherAlerts(now time.Time) {
+	today := now.Format("2006-01-02")
+	configMu.Lock()
+	defer configMu.Unlock()
+	for key := range config.SentWeatherAlerts {
+		parts := strings.Split(key, "|")
+		if len(parts) != 3 || parts[1] < today {
+			delete(config.SentWeatherAlerts, key)
+		}
+	}
+}
+
+func handleWeatherThresholds(s *discordgo.Session, m *discordgo.MessageCreate, lang, args string) {
+	fields := strings.Fields(args)
+	if len(fields) < 3 {
+		name := strings.TrimSpace(args)
+		if name == "" {
+			s.ChannelMessageSend(m.ChannelID, tr(lang, "thresholds.default", describeThresholds(lang, defaultWeatherThresholds)))
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "thresholds.for", name, describeThresholds(lang, thresholdsFor(name))))
+		return
+	}
+
+	value := fields[len(fields)-1]
+	param := fields[len(fields)-2]
+	name := strings.Join(fields[:len(fields)-2], " ")
+	t := thresholdsFor(name)
+
+	var err error
+	switch param {
+	case "burza":
+		t.Storm = value == "on" || value == "tak" || value == "yes"
+	case "mroz", "mróz":
+		t.FrostC, err = strconv.ParseFloat(value, 64)
+	case "upal", "upał":
+		t.HeatC, err = strconv.ParseFloat(value, 64)
+	case "opady":
+		t.PrecipMM, err = strconv.ParseFloat(value, 64)
+	case "porywy":
+		t.GustKmh, err = strconv.ParseFloat(value, 64)
+	default:
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "thresholds.unknown"))
+		return
+	}
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, tr(lang, "thresholds.bad_value"))
+		return
+	}
+
+	updateConfig(func(c *Config) {
+		if c.WeatherThresholds == nil {
+			c.WeatherThresholds = make(map[string]WeatherThresholds)
+		}
+		c.WeatherThresholds[strings.ToLower(name)] = t
+	})
+	s.ChannelMessageSend(m.ChannelID, tr(lang, "thresholds.saved", name, describeThresholds(lang, t)))
+}
+
+func describeThresholds(lang string, t WeatherThresholds) string {
+	storm := tr(lang, "thresholds.off")
+	if t.Storm {
+		storm = tr(lang, "thresholds.on")
+	}
+	return tr(lang, "thresholds.describe", t.FrostC, t.HeatC, t.PrecipMM, t.GustKmh, storm)
+}
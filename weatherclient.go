@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	openMeteoForecastURL  = "https://api.open-meteo.com/v1/forecast"
+	weatherForecastDays   = 7
+	defaultWeatherTimeout = 10 * time.Second
+	defaultWeatherTTL     = 30 * time.Minute
+)
+
+// weatherClient pobiera prognozy z Open-Meteo. Każde zapytanie obejmuje pełny
+// tydzień z danymi godzinowymi, więc jedna odpowiedź z cache obsługuje
+// prognozę na jutro, widok tygodniowy, godzinowy i ostrzeżenia.
+type weatherClient struct {
+	httpClient *http.Client
+	baseURL    string
+	timeout    time.Duration
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]weatherCacheEntry
+}
+
+type weatherCacheEntry struct {
+	resp    weatherResponse
+	fetched time.Time
+}
+
+var weatherAPI = newWeatherClient(&http.Client{Timeout: defaultWeatherTimeout}, openMeteoForecastURL, defaultWeatherTTL)
+
+func newWeatherClient(httpClient *http.Client, baseURL string, ttl time.Duration) *weatherClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &weatherClient{
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		timeout:    defaultWeatherTimeout,
+		ttl:        ttl,
+		now:        time.Now,
+		cache:      make(map[string]weatherCacheEntry),
+	}
+}
+
+// cacheKey łączy współrzędne z lokalną datą, żeby po północy nie serwować wczorajszej prognozy.
+func (c *weatherClient) cacheKey(lat, lon float64, units WeatherUnits) string {
+	date := c.now().Format("2006-01-02")
+	if loc, err := time.LoadLocation(defaultTimezone); err == nil {
+		date = c.now().In(loc).Format("2006-01-02")
+	}
+	return fmt.Sprintf("%.4f,%.4f|%s|%s,%s,%s", lat, lon, date, units.Temperature, units.WindSpeed, units.Precipitation)
+}
+
+func (c *weatherClient) fetch(ctx context.Context, lat, lon float64, units WeatherUnits) (weatherResponse, error) {
+	units = units.merge(metricUnits)
+	key := c.cacheKey(lat, lon, units)
+	now := c.now()
+
+	c.mu.Lock()
+	if entry, ok := c.cache[key]; ok && now.Sub(entry.fetched) < c.ttl {
+		c.mu.Unlock()
+		return entry.resp, nil
+	}
+	c.mu.Unlock()
+
+	started := time.Now()
+	resp, err := c.request(ctx, lat, lon, units)
+	observeAPI("open-meteo", time.Since(started), err)
+	if err != nil {
+		return weatherResponse{}, err
+	}
+
+	c.mu.Lock()
+	for k, entry := range c.cache {
+		if now.Sub(entry.fetched) >= c.ttl {
+			delete(c.cache, k)
+		}
+	}
+	c.cache[key] = weatherCacheEntry{resp: resp, fetched: now}
+	c.mu.Unlock()
+	return resp, nil
+}
+
+func (c *weatherClient) request(ctx context.Context, lat, lon float64, units WeatherUnits) (weatherResponse, error) {
+	q := url.Values{}
+	q.Set("latitude", fmt.Sprintf("%.4f", lat))
+	q.Set("longitude", fmt.Sprintf("%.4f", lon))
+	q.Set("daily", "temperature_2m_max,temperature_2m_min,weathercode,precipitation_sum,precipitation_probability_max,wind_speed_10m_max,wind_gusts_10m_max,uv_index_max,sunrise,sunset")
+	q.Set("hourly", "temperature_2m,precipitation_probability,wind_speed_10m")
+	q.Set("timezone", defaultTimezone)
+	q.Set("forecast_days", fmt.Sprint(weatherForecastDays))
+	q.Set("temperature_unit", units.Temperature)
+	q.Set("wind_speed_unit", units.WindSpeed)
+	q.Set("precipitation_unit", units.Precipitation)
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return weatherResponse{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return weatherResponse{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return weatherResponse{}, fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	var parsed weatherResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return weatherResponse{}, err
+	}
+	return parsed, nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOpenMeteo zlicza zapytania i zapamiętuje ich parametry.
+type fakeOpenMeteo struct {
+	mu      sync.Mutex
+	queries []url.Values
+	status  int
+}
+
+func (f *fakeOpenMeteo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.queries = append(f.queries, r.URL.Query())
+	status := f.status
+	f.mu.Unlock()
+	if status != 0 {
+		http.Error(w, "upstream down", status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"daily":{"time":["2026-10-18"]}}`))
+}
+
+func (f *fakeOpenMeteo) requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.queries)
+}
+
+func newTestWeatherClient(t *testing.T, api *fakeOpenMeteo, now *time.Time) *weatherClient {
+	t.Helper()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	c := newWeatherClient(srv.Client(), srv.URL, time.Hour)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestWeatherClientCacheTTL(t *testing.T) {
+	api := &fakeOpenMeteo{}
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	c := newTestWeatherClient(t, api, &now)
+	ctx := context.Background()
+
+	if _, err := c.fetch(ctx, 52.23, 21.01, metricUnits); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(59 * time.Minute)
+	if _, err := c.fetch(ctx, 52.23, 21.01, metricUnits); err != nil {
+		t.Fatal(err)
+	}
+	if got := api.requests(); got != 1 {
+		t.Fatalf("zapytania w TTL = %d, want 1", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := c.fetch(ctx, 52.23, 21.01, metricUnits); err != nil {
+		t.Fatal(err)
+	}
+	if got := api.requests(); got != 2 {
+		t.Fatalf("zapytania po TTL = %d, want 2", got)
+	}
+}
+
+func TestWeatherClientCacheKeys(t *testing.T) {
+	api := &fakeOpenMeteo{}
+	// 23:45 w Warszawie; pół godziny później jest już kolejny dzień
+	now := time.Date(2026, 10, 18, 21, 45, 0, 0, time.UTC)
+	c := newTestWeatherClient(t, api, &now)
+	c.ttl = 24 * time.Hour
+	ctx := context.Background()
+
+	imperial := WeatherUnits{Temperature: "fahrenheit", WindSpeed: "mph", Precipitation: "inch"}
+	steps := []struct {
+		advance time.Duration
+		units   WeatherUnits
+		want    int
+	}{
+		{0, metricUnits, 1},
+		{0, WeatherUnits{}, 1}, // puste jednostki to metryczne
+		{0, imperial, 2},
+		{0, imperial, 2},
+		{30 * time.Minute, metricUnits, 3}, // nowa data lokalna
+		{0, imperial, 4},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if _, err := c.fetch(ctx, 52.23, 21.01, step.units); err != nil {
+			t.Fatal(err)
+		}
+		if got := api.requests(); got != step.want {
+			t.Fatalf("krok %d: zapytania = %d, want %d", i, got, step.want)
+		}
+	}
+
+	if _, err := c.fetch(ctx, 50.06, 19.94, metricUnits); err != nil {
+		t.Fatal(err)
+	}
+	if got := api.requests(); got != 5 {
+		t.Fatalf("inne współrzędne: zapytania = %d, want 5", got)
+	}
+}
+
+func TestWeatherClientUnitParams(t *testing.T) {
+	api := &fakeOpenMeteo{}
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	c := newTestWeatherClient(t, api, &now)
+
+	units := WeatherUnits{Temperature: "fahrenheit", WindSpeed: "ms"}
+	if _, err := c.fetch(context.Background(), 52.2297, 21.0122, units); err != nil {
+		t.Fatal(err)
+	}
+	q := api.queries[0]
+	want := map[string]string{
+		"latitude":           "52.2297",
+		"longitude":          "21.0122",
+		"temperature_unit":   "fahrenheit",
+		"wind_speed_unit":    "ms",
+		"precipitation_unit": "mm",
+		"timezone":           defaultTimezone,
+		"forecast_days":      "7",
+	}
+	for k, v := range want {
+		if got := q.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestWeatherClientBadStatus(t *testing.T) {
+	api := &fakeOpenMeteo{status: http.StatusServiceUnavailable}
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	c := newTestWeatherClient(t, api, &now)
+	ctx := context.Background()
+
+	_, err := c.fetch(ctx, 52.23, 21.01, metricUnits)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("err = %v, want bad status 503", err)
+	}
+
+	// błędna odpowiedź nie trafia do cache
+	api.mu.Lock()
+	api.status = 0
+	api.mu.Unlock()
+	if _, err := c.fetch(ctx, 52.23, 21.01, metricUnits); err != nil {
+		t.Fatal(err)
+	}
+	if got := api.requests(); got != 2 {
+		t.Fatalf("zapytania = %d, want 2", got)
+	}
+}